@@ -0,0 +1,245 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//DefaultConcurrency Number of concurrent requests made by the batch helpers when Client.Concurrency is not set.
+const DefaultConcurrency = 4
+
+//DefaultMaxBatchSize Number of items the batch helpers accept when Client.MaxBatchSize is not set. At the rate limits of the API, larger batches take hours.
+const DefaultMaxBatchSize = 10000
+
+//ErrBatchTooLarge Wrapped by the error of the batch helpers given more items than Client.MaxBatchSize, returned before any request.
+var ErrBatchTooLarge = errors.New("too many items in the batch")
+
+//DomainErrors Errors of a batch lookup keyed by domain.
+type DomainErrors map[string]error
+
+func (e DomainErrors) Error() string {
+	domains := e.domains()
+	msgs := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", domain, e[domain]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+//Unwrap Returns the errors of every domain, so errors.Is and errors.As look into them.
+func (e DomainErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, domain := range e.domains() {
+		errs = append(errs, e[domain])
+	}
+	return errs
+}
+
+func (e DomainErrors) domains() []string {
+	domains := make([]string, 0, len(e))
+	for domain := range e {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+//checkBatch Fails with ErrBatchTooLarge when size exceeds the limit set by MaxBatchSize.
+func (c *Client) checkBatch(size int) error {
+	limit := c.MaxBatchSize
+	if limit == 0 {
+		limit = DefaultMaxBatchSize
+	}
+	if limit > 0 && size > limit {
+		return fmt.Errorf("%w: %d items, the limit is %d", ErrBatchTooLarge, size, limit)
+	}
+	return nil
+}
+
+func (c *Client) concurrency() int {
+	if c.Concurrency > 0 {
+		return c.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+//DomainsBreaches Fetches the breaches of each domain concurrently, using at most Client.Concurrency requests at a time. The domains that failed are left out of the returned map, and the error is a DomainErrors holding the reason for each of them. When ctx is done the domains not yet looked up fail with ctx.Err().
+func (c *Client) DomainsBreaches(ctx context.Context, domains []string) (map[string][]BreachModel, error) {
+	if err := c.checkBatch(len(domains)); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string][]BreachModel, len(domains))
+	errs := make(DomainErrors)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrency())
+	for _, domain := range domains {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[domain] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(domain string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			breaches, err := c.breaches(ctx, domain)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[domain] = err
+				return
+			}
+			results[domain] = breaches
+		}(domain)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
+
+//AccountResult Outcome of the lookup of one account by BreachedAccountsChan.
+type AccountResult struct {
+	Account  string
+	Breaches []BreachModel
+	Err      error
+}
+
+//BreachedAccountsChan Looks up the breaches of the accounts concurrently, including unverified ones, using at most Client.Concurrency requests at a time. Each result is sent on the returned channel as soon as its lookup finishes, so the order is not the one of accounts, and the channel is closed once all of them are sent. The channel is buffered for all the accounts, so a consumer stopping early does not leak goroutines. When ctx is done the accounts not yet looked up fail with ctx.Err(), and when there are more accounts than MaxBatchSize they all fail with ErrBatchTooLarge without any request.
+func (c *Client) BreachedAccountsChan(ctx context.Context, accounts []string) <-chan AccountResult {
+	results := make(chan AccountResult, len(accounts))
+	go func() {
+		c.lookupAccounts(ctx, accounts, func(_ int, res AccountResult) {
+			results <- res
+		})
+		close(results)
+	}()
+	return results
+}
+
+//BreachedAccounts Same as BreachedAccountsChan, but waits for all the lookups and returns their results in the order of accounts, so they line up with the input whatever order they complete in.
+func (c *Client) BreachedAccounts(ctx context.Context, accounts []string) []AccountResult {
+	results := make([]AccountResult, len(accounts))
+	c.lookupAccounts(ctx, accounts, func(i int, res AccountResult) {
+		results[i] = res
+	})
+	return results
+}
+
+//lookupAccounts Looks up the accounts concurrently, calling emit with the index of each account and its result as soon as it is known. emit may be called from several goroutines at once, but never twice for the same index.
+func (c *Client) lookupAccounts(ctx context.Context, accounts []string, emit func(i int, res AccountResult)) {
+	if err := c.checkBatch(len(accounts)); err != nil {
+		for i, account := range accounts {
+			emit(i, AccountResult{Account: account, Err: err})
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrency())
+	for i, account := range accounts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			emit(i, AccountResult{Account: account, Err: ctx.Err()})
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, account string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			breaches, err := c.breachedAccount(ctx, account, "", false, true)
+			emit(i, AccountResult{Account: account, Breaches: breaches, Err: err})
+		}(i, account)
+	}
+	wg.Wait()
+}
+
+//MostPwnedPassword Checks the passwords concurrently, using at most Client.Concurrency requests at a time, and returns the one appearing the most in the Pwned Passwords corpus along with its count. Ties go to the first one given, so when none of them is pwned the first password is returned with a count of 0. An empty list returns an empty password. Set Client.RangeCacheSize so variants sharing a hash prefix are checked with a single request.
+func (c *Client) MostPwnedPassword(passwords []string) (string, int, error) {
+	return c.MostPwnedPasswordContext(context.Background(), passwords)
+}
+
+//MostPwnedPasswordContext Same as MostPwnedPassword, the requests being bound to ctx. Once ctx is done no new lookup is started and ctx.Err() is returned.
+func (c *Client) MostPwnedPasswordContext(ctx context.Context, passwords []string) (string, int, error) {
+	if err := c.checkBatch(len(passwords)); err != nil {
+		return "", 0, err
+	}
+
+	counts := make([]int, len(passwords))
+	errs := make([]error, len(passwords))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrency())
+	for i, password := range passwords {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return "", 0, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, password string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			counts[i], errs[i] = c.PwnedPasswordContext(ctx, password)
+		}(i, password)
+	}
+	wg.Wait()
+
+	most := -1
+	for i := range passwords {
+		if errs[i] != nil {
+			return "", 0, errs[i]
+		}
+		if most < 0 || counts[i] > counts[most] {
+			most = i
+		}
+	}
+	if most < 0 {
+		return "", 0, nil
+	}
+	return passwords[most], counts[most], nil
+}
+
+//AnyBreached Looks up the aliases of a single person one after the other, stopping at the first one involved in a breach, unverified breaches included like BreachedAccounts. It returns true along with the breaches of that alias, or false if none of them was found.
+func (c *Client) AnyBreached(ctx context.Context, aliases []string) (bool, []BreachModel, error) {
+	if err := c.checkBatch(len(aliases)); err != nil {
+		return false, nil, err
+	}
+
+	for _, alias := range aliases {
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		breaches, err := c.breachedAccount(ctx, alias, "", false, true)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return false, nil, err
+		}
+		if len(breaches) > 0 {
+			return true, breaches, nil
+		}
+	}
+	return false, nil, nil
+}
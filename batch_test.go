@@ -0,0 +1,172 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDomainsBreaches(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("domain") {
+		case "adobe.com":
+			w.Write([]byte(`[{"Name":"Adobe","Domain":"adobe.com"}]`))
+		case "broken.com":
+			w.Write([]byte(`not json`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+
+	results, err := c.DomainsBreaches(context.Background(), []string{"adobe.com", "example.com", "broken.com"})
+	if len(results["adobe.com"]) != 1 {
+		t.Errorf("expected 1 breach for adobe.com, got %d", len(results["adobe.com"]))
+	}
+	if _, ok := results["example.com"]; !ok {
+		t.Error("expected a result for example.com")
+	}
+	errs, ok := err.(DomainErrors)
+	if !ok {
+		t.Fatalf("expected DomainErrors, got %v", err)
+	}
+	if len(errs) != 1 || errs["broken.com"] == nil {
+		t.Errorf("expected an error for broken.com only, got %v", errs)
+	}
+}
+
+func TestDomainErrorsUnwrap(t *testing.T) {
+	err := error(DomainErrors{"adobe.com": fmt.Errorf("breaches: %w", context.Canceled), "example.com": ErrNotFound})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the domain errors to be unwrapped, got %v", err)
+	}
+	if errors.Is(err, ErrForbidden) {
+		t.Error("expected ErrForbidden not to match")
+	}
+}
+
+func TestAnyBreached(t *testing.T) {
+	var lookups []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		lookups = append(lookups, r.URL.Path)
+		if r.URL.Query().Get("includeUnverified") != "true" {
+			t.Errorf("expected unverified breaches to be included, got %q", r.URL.RawQuery)
+		}
+		if r.URL.Path == "/breachedaccount/work@example.com" {
+			w.Write([]byte(`[{"Name":"Adobe"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	breached, breaches, err := c.AnyBreached(context.Background(), []string{"home@example.com", "work@example.com", "old@example.com"})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if !breached || len(breaches) != 1 {
+		t.Errorf("expected 1 breach, got %v", breaches)
+	}
+	if len(lookups) != 2 {
+		t.Errorf("expected to stop after 2 lookups, got %d", len(lookups))
+	}
+}
+
+func TestMostPwnedPassword(t *testing.T) {
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/range/5BAA6":
+			w.Write([]byte(passwordRange))
+		case "/range/E38AD":
+			w.Write([]byte("214943DAAD1D64C102FAEC29DE4AFE9DA3D:2413945\r\n"))
+		default:
+			w.Write([]byte("0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n"))
+		}
+	})
+
+	password, count, err := c.MostPwnedPassword([]string{"password1", "Password!", "password"})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if password != "password" || count != 9545824 {
+		t.Errorf("expected password seen 9545824 times, got %s seen %d times", password, count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := c.MostPwnedPasswordContext(ctx, []string{"password1", "password"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if password, count, err := c.MostPwnedPassword([]string{"Password!", "Tr0ub4dor&3x"}); err != nil || password != "Password!" || count != 0 {
+		t.Errorf("expected the first password with no count when none is pwned, got %q seen %d times (%v)", password, count, err)
+	}
+	if password, count, err := c.MostPwnedPassword(nil); err != nil || password != "" || count != 0 {
+		t.Errorf("expected an empty password for an empty list, got %q seen %d times (%v)", password, count, err)
+	}
+}
+
+func TestBreachedAccountsChan(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/breachedaccount/clean@example.com" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[{"Name":"Adobe"}]`))
+	})
+
+	results := make(map[string]AccountResult)
+	for res := range c.BreachedAccountsChan(context.Background(), []string{"pwned@example.com", "clean@example.com", " "}) {
+		results[res.Account] = res
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if res := results["pwned@example.com"]; res.Err != nil || len(res.Breaches) != 1 {
+		t.Errorf("unexpected result for the pwned account: %+v", res)
+	}
+	if res := results["clean@example.com"]; res.Err != nil || len(res.Breaches) != 0 {
+		t.Errorf("unexpected result for the clean account: %+v", res)
+	}
+	if res := results[" "]; res.Err != ErrEmptyAccount {
+		t.Errorf("expected ErrEmptyAccount, got %v", res.Err)
+	}
+}
+
+func TestBreachedAccounts(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/breachedaccount/slow@example.com" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Write([]byte(`[{"Name":"Adobe"}]`))
+	})
+
+	accounts := []string{"slow@example.com", "fast@example.com", "slow@example.com"}
+	results := c.BreachedAccounts(context.Background(), accounts)
+	if len(results) != len(accounts) {
+		t.Fatalf("expected %d results, got %d", len(accounts), len(results))
+	}
+	for i, res := range results {
+		if res.Account != accounts[i] || res.Err != nil || len(res.Breaches) != 1 {
+			t.Errorf("unexpected result %d: %+v", i, res)
+		}
+	}
+}
+
+func TestMaxBatchSize(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request for a batch too large, got %s", r.URL.Path)
+	})
+	c.MaxBatchSize = 2
+	accounts := []string{"a@example.com", "b@example.com", "c@example.com"}
+
+	if _, err := c.DomainsBreaches(context.Background(), accounts); !errors.Is(err, ErrBatchTooLarge) {
+		t.Errorf("expected ErrBatchTooLarge, got %v", err)
+	}
+	for _, res := range c.BreachedAccounts(context.Background(), accounts) {
+		if !errors.Is(res.Err, ErrBatchTooLarge) {
+			t.Errorf("%s: expected ErrBatchTooLarge, got %v", res.Account, res.Err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Cache Stores response bodies keyed by request URL, so repeated identical lookups can be served without calling the API. Implementations must be safe for concurrent use.
+type Cache interface {
+	//Get Returns the value stored for key, and false when it is missing or expired.
+	Get(key string) ([]byte, bool)
+	//Set Stores the value for key during ttl. A zero ttl means no expiration.
+	Set(key string, val []byte, ttl time.Duration)
+}
+
+//MemoryCache Cache kept in memory, for a single process. Its zero value is ready to use.
+//It has no size bound: an expired entry is only dropped when it is looked up again, and entries stored with a zero ttl, like the responses cached while PositiveCacheTTL is not set, stay until the process exits. Use a bounded Cache implementation for long running processes looking up many distinct accounts.
+type MemoryCache struct {
+	//Clock Replaces time.Now when computing and checking expirations, so tests can expire entries without sleeping.
+	Clock func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	val     []byte
+	expires time.Time
+}
+
+//NewMemoryCache Returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry)}
+}
+
+//Get Returns the value stored for key, and false when it is missing or expired.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && m.now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.val, true
+}
+
+//Set Stores the value for key during ttl. A zero ttl means no expiration.
+func (m *MemoryCache) Set(key string, val []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := memoryEntry{val: val}
+	if ttl > 0 {
+		e.expires = m.now().Add(ttl)
+	}
+	if m.entries == nil {
+		m.entries = make(map[string]memoryEntry)
+	}
+	m.entries[key] = e
+}
+
+func (m *MemoryCache) now() time.Time {
+	if m.Clock != nil {
+		return m.Clock()
+	}
+	return time.Now()
+}
+
+//breachesCache Last unfiltered list of breaches fetched by a Client.
+type breachesCache struct {
+	breaches []BreachModel
+	fetched  time.Time
+}
+
+//cachedBreaches Returns the unfiltered list of breaches, served from memory while it is younger than BreachesCacheTTL.
+func (c *Client) cachedBreaches(ctx context.Context) ([]BreachModel, error) {
+	if cached, ok := c.freshBreaches(); ok {
+		return cached, nil
+	}
+
+	breaches, err := c.BreachesContext(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if c.BreachesCacheTTL > 0 {
+		c.mu.Lock()
+		c.allBreaches = breachesCache{breaches: breaches, fetched: c.now()}
+		c.mu.Unlock()
+	}
+	return breaches, nil
+}
+
+//freshBreaches Returns the unfiltered list of breaches kept in memory, and false when there is none younger than BreachesCacheTTL.
+func (c *Client) freshBreaches() ([]BreachModel, bool) {
+	c.mu.Lock()
+	cached := c.allBreaches
+	c.mu.Unlock()
+
+	if c.BreachesCacheTTL > 0 && cached.breaches != nil && c.now().Sub(cached.fetched) < c.BreachesCacheTTL {
+		return cached.breaches, true
+	}
+	return nil, false
+}
+
+//BreachExists Reports whether a breach with the given name exists. The name is looked up case insensitively in the list of breaches kept in memory while it is younger than BreachesCacheTTL, and otherwise with a request for the single breach.
+func (c *Client) BreachExists(name string) (bool, error) {
+	return c.BreachExistsContext(context.Background(), name)
+}
+
+//BreachExistsContext Same as BreachExists, the request being bound to ctx.
+func (c *Client) BreachExistsContext(ctx context.Context, name string) (bool, error) {
+	if cached, ok := c.freshBreaches(); ok {
+		for _, b := range cached {
+			if strings.EqualFold(b.Name, name) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	breach, err := c.BreachContext(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return breach.Name != "", nil
+}
+
+//BreachesWithDataClass Returns the breaches exposing the given data class, like "Credit cards". HIBP has no server side filter for this, so the whole list is fetched and filtered locally, served from memory while it is younger than BreachesCacheTTL.
+func (c *Client) BreachesWithDataClass(class string) ([]BreachModel, error) {
+	return c.BreachesWithDataClassContext(context.Background(), class)
+}
+
+//BreachesWithDataClassContext Same as BreachesWithDataClass, the request being bound to ctx.
+func (c *Client) BreachesWithDataClassContext(ctx context.Context, class string) ([]BreachModel, error) {
+	breaches, err := c.cachedBreaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]BreachModel, 0)
+	for _, b := range breaches {
+		if b.Exposed(class) {
+			matching = append(matching, b)
+		}
+	}
+	return matching, nil
+}
+
+//BreachesMinPwnCount Returns the breaches with a PwnCount of at least threshold, to focus on the largest ones. The whole list is filtered locally, served from memory while it is younger than BreachesCacheTTL.
+func (c *Client) BreachesMinPwnCount(threshold int64) ([]BreachModel, error) {
+	return c.BreachesMinPwnCountContext(context.Background(), threshold)
+}
+
+//BreachesMinPwnCountContext Same as BreachesMinPwnCount, the request being bound to ctx.
+func (c *Client) BreachesMinPwnCountContext(ctx context.Context, threshold int64) ([]BreachModel, error) {
+	breaches, err := c.cachedBreaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]BreachModel, 0)
+	for _, b := range breaches {
+		if b.PwnCount >= threshold {
+			matching = append(matching, b)
+		}
+	}
+	return matching, nil
+}
+
+//LastChecked Returns when the breaches or pastes of the account were last successfully looked up by c from the API, a not found account being a successful lookup. Responses served from Cache do not count. It returns false when the account was never checked, was forgotten, or CheckedAccountsSize is not set.
+func (c *Client) LastChecked(account string) (time.Time, bool) {
+	checked := c.checkedLRU()
+	if checked == nil {
+		return time.Time{}, false
+	}
+	t, ok := checked.get(strings.ToLower(strings.TrimSpace(account)))
+	if !ok {
+		return time.Time{}, false
+	}
+	return t.(time.Time), true
+}
+
+func (c *Client) markChecked(account string) {
+	if checked := c.checkedLRU(); checked != nil {
+		checked.set(strings.ToLower(strings.TrimSpace(account)), c.now())
+	}
+}
+
+//checkedLRU Returns the last check times of the accounts, creating them on first use, or nil when CheckedAccountsSize is not set.
+func (c *Client) checkedLRU() *lru {
+	if c.CheckedAccountsSize <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checked == nil {
+		c.checked = newLRU(c.CheckedAccountsSize)
+	}
+	return c.checked
+}
@@ -0,0 +1,252 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreachesWithDataClass(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"Name":"Adobe","DataClasses":["Email addresses","Passwords"]},{"Name":"Shop","DataClasses":["Credit cards"]}]`))
+	})
+	c.BreachesCacheTTL = time.Minute
+
+	breaches, err := c.BreachesWithDataClass("credit cards")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(breaches) != 1 || breaches[0].Name != "Shop" {
+		t.Errorf("expected Shop, got %v", breaches)
+	}
+
+	breaches, err = c.BreachesWithDataClass(DataClassPasswords)
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(breaches) != 1 || breaches[0].Name != "Adobe" {
+		t.Errorf("expected Adobe, got %v", breaches)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestBreachesMinPwnCount(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe","PwnCount":152445165},{"Name":"Forum","PwnCount":1200},{"Name":"Shop","PwnCount":1000000}]`))
+	})
+
+	breaches, err := c.BreachesMinPwnCount(1000000)
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(breaches) != 2 || breaches[0].Name != "Adobe" || breaches[1].Name != "Shop" {
+		t.Errorf("expected Adobe and Shop, got %v", breaches)
+	}
+}
+
+func TestBreachExists(t *testing.T) {
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/breaches/":
+			w.Write([]byte(`[{"Name":"Adobe"}]`))
+		case "/breach/Adobe":
+			w.Write([]byte(`{"Name":"Adobe"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	for name, expected := range map[string]bool{"Adobe": true, "Adobee": false} {
+		exists, err := c.BreachExists(name)
+		if err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+		if exists != expected {
+			t.Errorf("%s: expected %v, got %v", name, expected, exists)
+		}
+	}
+	if len(paths) != 2 {
+		t.Errorf("expected a request per name without the cache, got %v", paths)
+	}
+
+	c.BreachesCacheTTL = time.Minute
+	if _, err := c.cachedBreaches(context.Background()); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	paths = nil
+	for name, expected := range map[string]bool{"adobe": true, "Adobee": false} {
+		if exists, _ := c.BreachExists(name); exists != expected {
+			t.Errorf("%s: expected %v, got %v", name, expected, exists)
+		}
+	}
+	if len(paths) != 0 {
+		t.Errorf("expected names to be checked against the cached list, got %v", paths)
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"Name":"Adobe"}]`))
+	})
+	c.Cache = NewMemoryCache()
+	c.PositiveCacheTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		breaches, err := c.BreachedAccount("test@example.com", "", false, false)
+		if err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+		if len(breaches) != 1 {
+			t.Errorf("expected 1 result, got %d", len(breaches))
+		}
+	}
+	if _, err := c.BreachedAccount("other@example.com", "", false, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestNormalizeAccounts(t *testing.T) {
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`[{"Name":"Adobe"}]`))
+	})
+	c.Cache = NewMemoryCache()
+	c.NormalizeAccounts = true
+
+	for _, account := range []string{"Foo@Example.com", " foo@example.com "} {
+		if _, err := c.BreachedAccount(account, "", true, false); err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+	}
+	if len(paths) != 1 || paths[0] != "/breachedaccount/foo@example.com" {
+		t.Errorf("expected a single request for the normalized account, got %v", paths)
+	}
+}
+
+func TestSendCachesOnlyGET(t *testing.T) {
+	var methods []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		methods = append(methods, r.Method+" "+string(body))
+		w.Write([]byte(`{}`))
+	})
+	c.Cache = NewMemoryCache()
+
+	for i := 0; i < 2; i++ {
+		req, err := c.newAPIRequest(context.Background(), http.MethodPost, c.BaseURL+"test", strings.NewReader("payload"), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := c.send("test", req); err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+	}
+	if len(methods) != 2 || methods[0] != "POST payload" {
+		t.Errorf("expected every POST to reach the API with its body, got %v", methods)
+	}
+}
+
+func TestLastChecked(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	WithClock(func() time.Time { return now })(c)
+	c.CheckedAccountsSize = 1
+
+	if _, err := c.BreachedAccount("foo@example.com", "", true, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if checked, ok := c.LastChecked("Foo@example.com"); !ok || !checked.Equal(now) {
+		t.Errorf("expected the account to be checked at %v, got %v", now, checked)
+	}
+
+	if _, err := c.BreachedAccount("bar@example.com", "", true, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if _, ok := c.LastChecked("foo@example.com"); ok {
+		t.Error("expected the least recently checked account to be forgotten")
+	}
+
+	c.Cache = NewMemoryCache()
+	c.NegativeCacheTTL = time.Hour
+	if _, err := c.BreachedAccount("bar@example.com", "", true, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := c.BreachedAccount("bar@example.com", "", true, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if checked, _ := c.LastChecked("bar@example.com"); !checked.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected a cached response not to mark the account as checked, got %v", checked)
+	}
+}
+
+func TestMemoryCacheExpiration(t *testing.T) {
+	now := time.Now()
+	m := NewMemoryCache()
+	m.Clock = func() time.Time { return now }
+	m.Set("key", []byte("val"), time.Minute)
+	if _, ok := m.Get("key"); !ok {
+		t.Error("expected the entry to be cached")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok := m.Get("key"); ok {
+		t.Error("expected the entry to be expired")
+	}
+}
+
+func TestMemoryCacheZeroValue(t *testing.T) {
+	var m MemoryCache
+	if _, ok := m.Get("key"); ok {
+		t.Error("expected an empty cache")
+	}
+	m.Set("key", []byte("val"), 0)
+	if val, ok := m.Get("key"); !ok || string(val) != "val" {
+		t.Errorf("expected val, got %q", val)
+	}
+}
+
+func TestNegativeCacheTTL(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	c.Cache = NewMemoryCache()
+
+	lookup := func() {
+		breaches, err := c.BreachedAccount("clean@example.com", "", false, false)
+		if err != nil || breaches != nil {
+			t.Fatalf("expected (nil, nil), got (%v, %v)", breaches, err)
+		}
+	}
+
+	lookup()
+	lookup()
+	if requests != 2 {
+		t.Errorf("expected not found results not to be cached by default, got %d requests", requests)
+	}
+
+	c.NegativeCacheTTL = time.Minute
+	lookup()
+	lookup()
+	if requests != 3 {
+		t.Errorf("expected the not found result to be cached, got %d requests", requests)
+	}
+}
@@ -0,0 +1,265 @@
+package haveibeenpwned
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Client Holds the settings used to query the API. The zero value is ready to use.
+type Client struct {
+	//BaseURL URL the services are resolved against, API when empty.
+	BaseURL string
+	//PasswordsURL URL the range searches are resolved against, PasswordsAPI when empty.
+	PasswordsURL string
+	//APIKey Key sent to the API, the HIBP_API_KEY environment variable when empty.
+	APIKey string
+	//APIKeyFunc Returns the key to send, overriding APIKey when set. It is called on every request, so it should be cheap, caching the key if fetching it is not.
+	APIKeyFunc func() string
+	//APIKeyHeader Header carrying the API key, DefaultAPIKeyHeader when empty. Useful behind a gateway expecting the key under another name.
+	APIKeyHeader string
+	//HTTPClient Client used to send the requests, a new http.Client when nil.
+	HTTPClient *http.Client
+	//Interceptors Wrap the transport of HTTPClient, or http.DefaultTransport when it has none. The first interceptor is the outermost: it sees the request first and the response last.
+	Interceptors []Interceptor
+	//RequestModifier Called with every request right before it is sent, after the headers set by the package like the API key and User-Agent, so it can add headers like correlation IDs or override the package ones.
+	RequestModifier func(*http.Request)
+	//Tracer Starts a span around each request when set, see Tracer.
+	Tracer Tracer
+	//MaxResponseBytes Upper bound for the size of a response body. Zero means no limit.
+	MaxResponseBytes int64
+	//Concurrency Maximum number of concurrent requests made by the batch helpers, DefaultConcurrency when zero.
+	Concurrency int
+	//MaxBatchSize Maximum number of items accepted by the batch helpers, which fail with ErrBatchTooLarge beyond it. DefaultMaxBatchSize when zero, and no limit when negative.
+	MaxBatchSize int
+	//TolerantDecode Decodes the elements of a breaches array one by one, so a malformed element is skipped instead of failing the whole response. The breaches decoded are returned along with a DecodeErrors.
+	TolerantDecode bool
+	//NotFoundIsError Makes BreachedAccount and PasteAccount return ErrNotFound when the account is not found, instead of an empty result.
+	NotFoundIsError bool
+	//NormalizeAccounts Trims white spaces and lowercases the accounts given to BreachedAccount and PasteAccount before building the URL, so variations of the same account share their Cache entries. The API does the same on its side.
+	NormalizeAccounts bool
+	//DefaultIncludeUnverified Includes unverified breaches in every BreachedAccount lookup, as if unverified was always set. Verified only lookups can then be done by filtering on IsVerified.
+	DefaultIncludeUnverified bool
+	//Cache Stores the responses of the API, no caching is done when nil.
+	Cache Cache
+	//PositiveCacheTTL How long a found result is kept in Cache. Zero means no expiration.
+	PositiveCacheTTL time.Duration
+	//NegativeCacheTTL How long a not found result is kept in Cache, usually shorter than PositiveCacheTTL since a clean account can be breached at any time. Zero disables the caching of not found results.
+	NegativeCacheTTL time.Duration
+	//OfflinePasswordsFile Path of the downloaded Pwned Passwords file read by OfflineRange.
+	OfflinePasswordsFile string
+	//RangeCacheSize Number of password range responses kept in memory, keyed by hash prefix, so passwords sharing a prefix are checked with a single request. Zero disables the cache.
+	RangeCacheSize int
+	//KeepRangeResponses Keeps the raw body of the range responses in the PasswordResult returned by CheckPassword, for audit logs. Off by default so no extra data is retained.
+	KeepRangeResponses bool
+	//BreachesCacheTTL How long the list of all breaches is kept in memory by the helpers built on it. Zero disables the cache.
+	BreachesCacheTTL time.Duration
+	//DataClassesTTL How long the list of data classes is kept in memory by DataClasses. Zero means DefaultDataClassesTTL.
+	DataClassesTTL time.Duration
+	//CheckedAccountsSize Number of accounts whose last successful lookup time is remembered for LastChecked, the least recently checked ones being forgotten first. Zero disables the tracking.
+	CheckedAccountsSize int
+
+	mu          sync.Mutex
+	validators  map[string]validator
+	rateLimit   RateLimit
+	allBreaches breachesCache
+	dataClasses dataClassesCache
+	ranges      *lru
+	checked     *lru
+	clock       func() time.Time
+	serverTime  time.Time
+}
+
+//validator Values used to send a conditional request for a single breach.
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+//DefaultClient Client used by the package level functions.
+var DefaultClient = &Client{}
+
+//NewClient Returns a Client configured with the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//Interceptor Wraps the http.RoundTripper sending the requests, to inspect or change requests and responses, like adding tracing headers or replaying recorded responses in tests.
+type Interceptor func(next http.RoundTripper) http.RoundTripper
+
+func (c *Client) httpClient() *http.Client {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	if len(c.Interceptors) == 0 {
+		return client
+	}
+
+	wrapped := *client
+	rt := wrapped.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		rt = c.Interceptors[i](rt)
+	}
+	wrapped.Transport = rt
+	return &wrapped
+}
+
+//doHTTP Sends the request with httpClient, once RequestModifier had a chance to change it.
+func (c *Client) doHTTP(req *http.Request) (*http.Response, error) {
+	if c.RequestModifier != nil {
+		c.RequestModifier(req)
+	}
+	return c.httpClient().Do(req)
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return API
+}
+
+//now Returns the current time from the clock set by WithClock, or time.Now.
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+//LastServerTime Returns the time given by the Date header of the last response of the API, to compute ages relative to the clock of HIBP rather than a possibly skewed local one. It returns false until a response with a valid Date header is received.
+func (c *Client) LastServerTime() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.serverTime, !c.serverTime.IsZero()
+}
+
+func (c *Client) trackServerTime(h http.Header) {
+	t, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serverTime = t
+}
+
+//HasAPIKey Reports whether an API key is configured, either by APIKeyFunc, on APIKey or in the HIBP_API_KEY environment variable, without calling the API. It does not tell whether the key is valid.
+func (c *Client) HasAPIKey() bool {
+	return c.apiKey() != ""
+}
+
+func (c *Client) apiKey() string {
+	if c.APIKeyFunc != nil {
+		return c.APIKeyFunc()
+	}
+	if c.APIKey != "" {
+		return c.APIKey
+	}
+	return os.Getenv("HIBP_API_KEY")
+}
+
+func (c *Client) apiKeyHeader() string {
+	if c.APIKeyHeader != "" {
+		return c.APIKeyHeader
+	}
+	return DefaultAPIKeyHeader
+}
+
+func (c *Client) readBody(res *http.Response) ([]byte, error) {
+	defer res.Body.Close()
+
+	if c.MaxResponseBytes <= 0 {
+		return ioutil.ReadAll(res.Body)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, c.MaxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.MaxResponseBytes {
+		return nil, errBodyTooLarge(c.MaxResponseBytes)
+	}
+	return body, nil
+}
+
+func errBodyTooLarge(limit int64) error {
+	return fmt.Errorf("response body exceeds the limit of %d bytes", limit)
+}
+
+//limitBody Bounds a response body read as a stream by MaxResponseBytes, failing the read that crosses the limit.
+func (c *Client) limitBody(body io.ReadCloser) io.ReadCloser {
+	if c.MaxResponseBytes <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, r: io.LimitReader(body, c.MaxResponseBytes+1), limit: c.MaxResponseBytes}
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.read > l.limit {
+		return 0, errBodyTooLarge(l.limit)
+	}
+	n, err := l.r.Read(p)
+	before := l.read
+	l.read += int64(n)
+	if l.read > l.limit {
+		return int(l.limit - before), errBodyTooLarge(l.limit)
+	}
+	return n, err
+}
+
+func (c *Client) validator(name string) (validator, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.validators[name]
+	return v, ok
+}
+
+func (c *Client) setValidator(name string, v validator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v == (validator{}) {
+		delete(c.validators, name)
+		return
+	}
+	if c.validators == nil {
+		c.validators = make(map[string]validator)
+	}
+	c.validators[name] = v
+}
+
+//readJSON Same as readBody, but fails with a clear error when the response is not JSON, like the HTML of a challenge page served with a 200.
+func (c *Client) readJSON(res *http.Response) ([]byte, error) {
+	if err := checkJSON(res); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	return c.readBody(res)
+}
+
+func checkJSON(res *http.Response) error {
+	if ct := res.Header.Get("Content-Type"); !strings.Contains(ct, "application/json") {
+		return fmt.Errorf("expected JSON, got %s", ct)
+	}
+	return nil
+}
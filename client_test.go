@@ -0,0 +1,241 @@
+package haveibeenpwned
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return &Client{BaseURL: server.URL + "/", APIKey: "test-key"}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Yahoo"}]`))
+	})
+
+	c.MaxResponseBytes = 10
+	_, err := c.Breaches("")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("expected a size limit error, got: %s", err)
+	}
+
+	c.MaxResponseBytes = 1024
+	breaches, err := c.Breaches("")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(breaches) != 2 {
+		t.Errorf("expected 2 results, got %d", len(breaches))
+	}
+}
+
+func TestLimitBodySmallReads(t *testing.T) {
+	c := &Client{MaxResponseBytes: 10}
+	body := c.limitBody(ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 25))))
+
+	read := 0
+	buf := make([]byte, 3)
+	for i := 0; i < 10; i++ {
+		n, err := body.Read(buf)
+		if n < 0 || n > len(buf) {
+			t.Fatalf("read %d returned an invalid count %d", i, n)
+		}
+		read += n
+		if read > 10 {
+			t.Fatalf("expected at most 10 bytes, got %d", read)
+		}
+		if read == 10 && (err == nil || !strings.Contains(err.Error(), "exceeds the limit of 10 bytes")) {
+			t.Errorf("read %d: expected the limit error once the limit is reached, got %v", i, err)
+		}
+	}
+	if read != 10 {
+		t.Errorf("expected the 10 bytes under the limit, got %d", read)
+	}
+	if _, err := ioutil.ReadAll(c.limitBody(ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 25))))); err == nil {
+		t.Error("expected ReadAll to fail past the limit")
+	}
+}
+
+func TestUnexpectedContentType(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write([]byte(`<html>Just a moment...</html>`))
+	})
+	_, err := c.Breaches("")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Error() != "breaches: expected JSON, got text/html; charset=UTF-8" {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestAPIKeyHeader(t *testing.T) {
+	var header http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`[]`))
+	})
+	c.APIKeyHeader = "X-Gateway-Key"
+	if _, err := c.BreachedAccount("test@example.com", "", false, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if _, ok := header["X-Gateway-Key"]; !ok {
+		t.Error("expected the key to be sent as X-Gateway-Key")
+	}
+	if _, ok := header["Hibp-Api-Key"]; ok {
+		t.Error("expected no hibp-api-key header")
+	}
+}
+
+func TestHasAPIKey(t *testing.T) {
+	t.Setenv("HIBP_API_KEY", "")
+	c := &Client{}
+	if c.HasAPIKey() {
+		t.Error("expected no API key")
+	}
+
+	t.Setenv("HIBP_API_KEY", "from-env")
+	if !c.HasAPIKey() {
+		t.Error("expected the API key from the environment")
+	}
+
+	t.Setenv("HIBP_API_KEY", "")
+	c.APIKey = "from-field"
+	if !c.HasAPIKey() {
+		t.Error("expected the API key from the field")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestInterceptors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Name":"` + r.Header.Get("X-Order") + `"}`))
+	})
+	tag := func(name string) Interceptor {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Order", req.Header.Get("X-Order")+name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	c.Interceptors = []Interceptor{tag("first"), tag("second")}
+
+	breach, err := c.Breach("Adobe")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if breach.Name != "firstsecond" {
+		t.Errorf("expected interceptors to run in order, got %s", breach.Name)
+	}
+}
+
+func TestWithoutAPIKey(t *testing.T) {
+	t.Setenv("HIBP_API_KEY", "")
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if _, ok := r.Header[http.CanonicalHeaderKey(DefaultAPIKeyHeader)]; ok {
+			t.Error("expected no API key header")
+		}
+		w.Write([]byte(`[]`))
+	})
+	c.APIKey = ""
+
+	if _, err := c.Breaches(""); err != nil {
+		t.Fatalf("expected public endpoints to work without a key, got %v", err)
+	}
+	if _, err := c.BreachedAccount("test@example.com", "", false, false); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := c.PasteAccount("test@example.com"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("expected only the public endpoint to be called, got %v", paths)
+	}
+}
+
+func TestRequestModifier(t *testing.T) {
+	var header http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`[]`))
+	})
+	c.RequestModifier = func(req *http.Request) {
+		req.Header.Set("X-Correlation-ID", "42")
+		req.Header.Set("User-Agent", "custom")
+	}
+
+	if _, err := c.Breaches(""); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if header.Get("X-Correlation-ID") != "42" {
+		t.Error("expected the correlation header to be added")
+	}
+	if header.Get("User-Agent") != "custom" {
+		t.Errorf("expected the modifier to override the User-Agent, got %q", header.Get("User-Agent"))
+	}
+}
+
+func TestAPIKeyFunc(t *testing.T) {
+	var keys []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(DefaultAPIKeyHeader))
+		w.Write([]byte(`[]`))
+	})
+	c.APIKey = "static"
+	rotations := 0
+	c.APIKeyFunc = func() string {
+		rotations++
+		return "rotated-" + string(rune('0'+rotations))
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.BreachedAccount("test@example.com", "", false, false); err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+	}
+	if len(keys) != 2 || keys[0] != "rotated-1" || keys[1] != "rotated-2" {
+		t.Errorf("expected the rotated keys, got %v", keys)
+	}
+}
+
+func TestLastServerTime(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", "Tue, 15 Nov 1994 08:12:31 GMT")
+		w.Write([]byte(`[]`))
+	})
+	if _, ok := c.LastServerTime(); ok {
+		t.Error("expected no server time before the first call")
+	}
+
+	if _, err := c.Breaches(""); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	st, ok := c.LastServerTime()
+	if !ok || !st.Equal(time.Date(1994, 11, 15, 8, 12, 31, 0, time.UTC)) {
+		t.Errorf("unexpected server time: %v", st)
+	}
+}
@@ -0,0 +1,61 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//csvHeader Columns of the CSV written by StreamBreachesCSV, named after the JSON fields.
+var csvHeader = []string{"Name", "Title", "Domain", "BreachDate", "AddedDate", "ModifiedDate", "PwnCount", "Description", "DataClasses", "IsVerified", "IsFabricated", "IsSensitive", "IsRetired", "IsSpamList", "LogoPath"}
+
+//csvFlushEvery Number of rows written by StreamBreachesCSV between two flushes.
+const csvFlushEvery = 100
+
+//StreamBreachesCSV Writes all breaches in the system to w as CSV, with a header row, while the response is decoded one breach at a time so memory stays flat whatever the size of the list. DataClasses are joined with ";".
+func (c *Client) StreamBreachesCSV(ctx context.Context, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := c.streamBreaches(ctx, func(b BreachModel) error {
+		if err := cw.Write(b.csvRecord()); err != nil {
+			return err
+		}
+		rows++
+		if rows%csvFlushEvery == 0 {
+			cw.Flush()
+			return cw.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (b BreachModel) csvRecord() []string {
+	return []string{
+		b.Name,
+		b.Title,
+		b.Domain,
+		b.BreachDate,
+		b.AddedDate,
+		b.ModifiedDate,
+		strconv.FormatInt(b.PwnCount, 10),
+		b.Description,
+		strings.Join(b.DataClasses, ";"),
+		strconv.FormatBool(b.IsVerified),
+		strconv.FormatBool(b.IsFabricated),
+		strconv.FormatBool(b.IsSensitive),
+		strconv.FormatBool(b.IsRetired),
+		strconv.FormatBool(b.IsSpamList),
+		b.LogoPath,
+	}
+}
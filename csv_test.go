@@ -0,0 +1,31 @@
+package haveibeenpwned
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStreamBreachesCSV(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe","Title":"Adobe, Inc.","PwnCount":152445165,"DataClasses":["Email addresses","Passwords"],"IsVerified":true}]`))
+	})
+
+	var buf bytes.Buffer
+	if err := c.StreamBreachesCSV(context.Background(), &buf); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a header and a row, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "Name,Title,Domain,") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if lines[1] != `Adobe,"Adobe, Inc.",,,,,152445165,,Email addresses;Passwords,true,false,false,false,false,` {
+		t.Errorf("unexpected row: %s", lines[1])
+	}
+}
@@ -0,0 +1,148 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+//DefaultDataClassesTTL How long the list of data classes is kept in memory when Client.DataClassesTTL is not set. The list rarely changes.
+const DefaultDataClassesTTL = 24 * time.Hour
+
+//Common data classes, as returned by the dataclasses service and found in BreachModel.DataClasses.
+const (
+	DataClassEmailAddresses          = "Email addresses"
+	DataClassPasswords               = "Passwords"
+	DataClassPasswordHints           = "Password hints"
+	DataClassUsernames               = "Usernames"
+	DataClassNames                   = "Names"
+	DataClassPhoneNumbers            = "Phone numbers"
+	DataClassPhysicalAddresses       = "Physical addresses"
+	DataClassIPAddresses             = "IP addresses"
+	DataClassDatesOfBirth            = "Dates of birth"
+	DataClassGenders                 = "Genders"
+	DataClassGeographicLocations     = "Geographic locations"
+	DataClassCreditCards             = "Credit cards"
+	DataClassPartialCreditCardData   = "Partial credit card data"
+	DataClassBankAccountNumbers      = "Bank account numbers"
+	DataClassSecurityQuestions       = "Security questions and answers"
+	DataClassSocialSecurityNumbers   = "Social security numbers"
+	DataClassGovernmentIssuedIDs     = "Government issued IDs"
+	DataClassAuthTokens              = "Auth tokens"
+	DataClassBrowserUserAgentDetails = "Browser user agent details"
+	DataClassEmployers               = "Employers"
+	DataClassJobTitles               = "Job titles"
+	DataClassPurchases               = "Purchases"
+	DataClassChatLogs                = "Chat logs"
+	DataClassPrivateMessages         = "Private messages"
+	DataClassSexualOrientations      = "Sexual orientations"
+	DataClassHealthInsuranceInfo     = "Health insurance information"
+	DataClassHistoricalPasswords     = "Historical passwords"
+	DataClassDeviceInformation       = "Device information"
+	DataClassSocialMediaProfiles     = "Social media profiles"
+	DataClassWebsiteActivity         = "Website activity"
+)
+
+//HasPasswords Reports whether the breach exposed passwords.
+func (b BreachModel) HasPasswords() bool {
+	return b.Exposed(DataClassPasswords)
+}
+
+//dataClassesCache Last list of data classes fetched by a Client.
+type dataClassesCache struct {
+	classes []string
+	fetched time.Time
+}
+
+//DataClasses Returns all the data classes in the system, served from memory while the last fetched list is younger than Client.DataClassesTTL. Use it to validate the classes given to BreachesWithDataClass. The returned slice is a copy the caller may change.
+func (c *Client) DataClasses() ([]string, error) {
+	return c.DataClassesContext(context.Background())
+}
+
+//DataClassesContext Same as DataClasses, the request being bound to ctx.
+func (c *Client) DataClassesContext(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	cached := c.dataClasses
+	c.mu.Unlock()
+
+	if cached.classes != nil && c.now().Sub(cached.fetched) < c.dataClassesTTL() {
+		return append([]string{}, cached.classes...), nil
+	}
+	return c.RefreshDataClassesContext(ctx)
+}
+
+//RefreshDataClasses Fetches the data classes, bypassing and replacing the list kept in memory by DataClasses.
+func (c *Client) RefreshDataClasses() ([]string, error) {
+	return c.RefreshDataClassesContext(context.Background())
+}
+
+//RefreshDataClassesContext Same as RefreshDataClasses, the request being bound to ctx.
+func (c *Client) RefreshDataClassesContext(ctx context.Context) ([]string, error) {
+	body, err := c.callService(ctx, "dataclasses", "", "", true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	classes := make([]string, 0)
+	if body != nil {
+		if err := json.Unmarshal(body, &classes); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	c.dataClasses = dataClassesCache{classes: classes, fetched: c.now()}
+	c.mu.Unlock()
+	return append([]string{}, classes...), nil
+}
+
+func (c *Client) dataClassesTTL() time.Duration {
+	if c.DataClassesTTL > 0 {
+		return c.DataClassesTTL
+	}
+	return DefaultDataClassesTTL
+}
+
+//DataClassHistogram Counts how many of the breaches expose each data class. Data classes differing only by case are counted together, under the first spelling met.
+func DataClassHistogram(breaches []BreachModel) map[string]int {
+	return dataClassHistogram(breaches, nil)
+}
+
+//DataClassHistogram Same as the package level DataClassHistogram, the data classes being counted under their spelling in DataClasses.
+func (c *Client) DataClassHistogram(breaches []BreachModel) (map[string]int, error) {
+	return c.DataClassHistogramContext(context.Background(), breaches)
+}
+
+//DataClassHistogramContext Same as DataClassHistogram, the request being bound to ctx.
+func (c *Client) DataClassHistogramContext(ctx context.Context, breaches []BreachModel) (map[string]int, error) {
+	classes, err := c.DataClassesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataClassHistogram(breaches, classes), nil
+}
+
+func dataClassHistogram(breaches []BreachModel, canonical []string) map[string]int {
+	names := make(map[string]string, len(canonical))
+	for _, dc := range canonical {
+		names[strings.ToLower(dc)] = dc
+	}
+
+	histogram := make(map[string]int)
+	for _, b := range breaches {
+		counted := make(map[string]bool, len(b.DataClasses))
+		for _, dc := range b.DataClasses {
+			key := strings.ToLower(dc)
+			if counted[key] {
+				continue
+			}
+			counted[key] = true
+			if _, ok := names[key]; !ok {
+				names[key] = dc
+			}
+			histogram[names[key]]++
+		}
+	}
+	return histogram
+}
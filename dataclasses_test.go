@@ -0,0 +1,70 @@
+package haveibeenpwned
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHasPasswords(t *testing.T) {
+	if !(BreachModel{DataClasses: []string{"Email addresses", "Passwords"}}).HasPasswords() {
+		t.Error("expected passwords to be exposed")
+	}
+	if (BreachModel{DataClasses: []string{DataClassPasswordHints}}).HasPasswords() {
+		t.Error("expected passwords not to be exposed")
+	}
+}
+
+func TestDataClasses(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/dataclasses/" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Write([]byte(`["Account balances","Age groups"]`))
+	})
+
+	for i := 0; i < 2; i++ {
+		classes, err := c.DataClasses()
+		if err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+		if len(classes) != 2 || classes[0] != "Account balances" {
+			t.Errorf("unexpected data classes: %v", classes)
+		}
+		classes[0] = "changed by the caller"
+	}
+	if requests != 1 {
+		t.Errorf("expected the list to be cached, got %d requests", requests)
+	}
+
+	if _, err := c.RefreshDataClasses(); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected a refresh to call the API, got %d requests", requests)
+	}
+}
+
+func TestDataClassHistogram(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["Email addresses","Passwords"]`))
+	})
+	breaches := []BreachModel{
+		{DataClasses: []string{"email addresses", "Passwords"}},
+		{DataClasses: []string{"Email Addresses", "email addresses"}},
+	}
+
+	histogram := DataClassHistogram(breaches)
+	if len(histogram) != 2 || histogram["email addresses"] != 2 || histogram["Passwords"] != 1 {
+		t.Errorf("unexpected histogram: %v", histogram)
+	}
+
+	histogram, err := c.DataClassHistogram(breaches)
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(histogram) != 2 || histogram[DataClassEmailAddresses] != 2 || histogram[DataClassPasswords] != 1 {
+		t.Errorf("expected the canonical names, got %v", histogram)
+	}
+}
@@ -0,0 +1,62 @@
+package haveibeenpwned
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//ElementError Failure to decode a single element of an array returned by the API.
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+func (e ElementError) Error() string {
+	return fmt.Sprintf("element %d: %s", e.Index, e.Err)
+}
+
+func (e ElementError) Unwrap() error {
+	return e.Err
+}
+
+//DecodeErrors Errors of the elements skipped by a tolerant decode, see Client.TolerantDecode.
+type DecodeErrors []ElementError
+
+func (e DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d elements could not be decoded: %s", len(e), strings.Join(msgs, "; "))
+}
+
+//decodeBreaches Decodes an array of breaches. With TolerantDecode the elements are decoded one by one, and the ones failing are skipped and reported in a DecodeErrors returned along with the others.
+func (c *Client) decodeBreaches(body []byte) ([]BreachModel, error) {
+	breaches := make([]BreachModel, 0)
+	if !c.TolerantDecode {
+		if err := json.Unmarshal(body, &breaches); err != nil {
+			return nil, err
+		}
+		return breaches, nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(body, &elements); err != nil {
+		return nil, err
+	}
+
+	var errs DecodeErrors
+	for i, element := range elements {
+		var b BreachModel
+		if err := json.Unmarshal(element, &b); err != nil {
+			errs = append(errs, ElementError{Index: i, Err: err})
+			continue
+		}
+		breaches = append(breaches, b)
+	}
+	if len(errs) > 0 {
+		return breaches, errs
+	}
+	return breaches, nil
+}
@@ -0,0 +1,29 @@
+package haveibeenpwned
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTolerantDecode(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe","PwnCount":152445165},{"Name":"Bad","PwnCount":"many"},{"Name":"Yahoo"}]`))
+	})
+
+	if _, err := c.Breaches(""); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	c.TolerantDecode = true
+	breaches, err := c.Breaches("")
+	if len(breaches) != 2 {
+		t.Errorf("expected 2 results, got %d", len(breaches))
+	}
+	errs, ok := err.(DecodeErrors)
+	if !ok {
+		t.Fatalf("expected DecodeErrors, got %v", err)
+	}
+	if len(errs) != 1 || errs[0].Index != 1 {
+		t.Errorf("expected element 1 to fail, got %v", errs)
+	}
+}
@@ -0,0 +1,82 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+//DiagnosticsReport Outcome of the checks run by Diagnostics, one per host and for the API key.
+type DiagnosticsReport struct {
+	//API Connection, TLS included, to BaseURL, using a public endpoint.
+	API Check
+	//Auth Acceptance of the API key by BaseURL.
+	Auth Check
+	//Passwords Connection, TLS included, to PasswordsURL.
+	Passwords Check
+}
+
+//Check Outcome of a single diagnostic. Err is nil when it passed.
+type Check struct {
+	URL string
+	Err error
+}
+
+//OK Reports whether the check passed.
+func (ch Check) OK() bool {
+	return ch.Err == nil
+}
+
+//OK Reports whether all checks passed.
+func (r DiagnosticsReport) OK() bool {
+	return r.API.OK() && r.Auth.OK() && r.Passwords.OK()
+}
+
+//Diagnostics Checks that both the API and the Pwned Passwords hosts can be reached with the settings of c, and that the API key is accepted, to troubleshoot networks allowing one host but blocking the other. Each check makes a single request and they all run even when one fails.
+func (c *Client) Diagnostics(ctx context.Context) DiagnosticsReport {
+	return DiagnosticsReport{
+		API:       c.check(ctx, "dataclasses", ""),
+		Auth:      c.check(ctx, "subscription", "status"),
+		Passwords: c.checkPasswords(ctx),
+	}
+}
+
+func (c *Client) check(ctx context.Context, service, account string) Check {
+	req, err := c.newRequest(ctx, service, account, "", true, false)
+	if err != nil {
+		return Check{URL: c.baseURL() + service, Err: err}
+	}
+	ch := Check{URL: req.URL.String()}
+
+	res, err := c.do(service, req)
+	if err != nil {
+		ch.Err = err
+		return ch
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		ch.Err = fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	return ch
+}
+
+func (c *Client) checkPasswords(ctx context.Context) Check {
+	ch := Check{URL: c.passwordsURL() + "range/00000"}
+	req, err := http.NewRequestWithContext(ctx, "GET", ch.URL, nil)
+	if err != nil {
+		ch.Err = err
+		return ch
+	}
+	req.Header.Set("User-Agent", "Go/1.15")
+
+	res, err := c.doHTTP(req)
+	if err != nil {
+		ch.Err = err
+		return ch
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		ch.Err = fmt.Errorf("unexpected status from the range API: %s", res.Status)
+	}
+	return ch
+}
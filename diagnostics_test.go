@@ -0,0 +1,33 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/subscription/status" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	c.PasswordsURL = "http://127.0.0.1:0/"
+
+	report := c.Diagnostics(context.Background())
+	if !report.API.OK() {
+		t.Errorf("expected the API to be reachable, got %v", report.API.Err)
+	}
+	if !errors.Is(report.Auth.Err, ErrUnauthorized) {
+		t.Errorf("expected the key to be rejected, got %v", report.Auth.Err)
+	}
+	if report.Passwords.OK() {
+		t.Error("expected the passwords host to be unreachable")
+	}
+	if report.OK() {
+		t.Error("expected the report to fail")
+	}
+}
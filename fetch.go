@@ -0,0 +1,48 @@
+//go:build go1.18
+
+package haveibeenpwned
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Fetch Calls the API at path, relative to the base URL of c, with the given query parameters, and decodes the response into T. It lets callers decode into their own types, like models with typed dates or fields this package does not know yet. A 404 leaves the zero value of T, or returns ErrNotFound when Client.NotFoundIsError is set. Responses are not cached.
+func Fetch[T any](c *Client, ctx context.Context, path string, params url.Values) (T, error) {
+	var v T
+
+	u, err := url.Parse(c.baseURL())
+	if err != nil {
+		return v, err
+	}
+	u.Path += strings.TrimPrefix(path, "/")
+	u.RawQuery = params.Encode()
+
+	service := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	req, err := c.newAPIRequest(ctx, http.MethodGet, u.String(), nil, !publicService(service))
+	if err != nil {
+		return v, err
+	}
+
+	res, err := c.do(service, req)
+	if err != nil {
+		return v, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		if c.NotFoundIsError {
+			return v, ErrNotFound
+		}
+		return v, nil
+	}
+
+	body, err := c.readJSON(res)
+	if err != nil {
+		return v, err
+	}
+	err = json.Unmarshal(body, &v)
+	return v, err
+}
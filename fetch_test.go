@@ -0,0 +1,34 @@
+//go:build go1.18
+
+package haveibeenpwned
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Path + "?" + r.URL.RawQuery
+		w.Write([]byte(`{"Name":"Adobe","AddedDate":"2013-12-04T00:00:00Z"}`))
+	})
+
+	type breach struct {
+		Name      string
+		AddedDate time.Time
+	}
+	b, err := Fetch[breach](c, context.Background(), "breach/Adobe", url.Values{"truncateResponse": {"false"}})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if query != "/breach/Adobe?truncateResponse=false" {
+		t.Errorf("unexpected request: %s", query)
+	}
+	if b.Name != "Adobe" || b.AddedDate.Year() != 2013 {
+		t.Errorf("unexpected breach: %+v", b)
+	}
+}
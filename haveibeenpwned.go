@@ -3,17 +3,43 @@
 package haveibeenpwned
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"io"
 	"net/http"
+	"net/mail"
 	"net/url"
-	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 //API URL of haveibeenpwned.com
 const API = "https://haveibeenpwned.com/api/v3/"
 
+//DefaultAPIKeyHeader Header carrying the API key when Client.APIKeyHeader is not set.
+const DefaultAPIKeyHeader = "hibp-api-key"
+
+//ErrEmptyAccount Returned without calling the API when the account to be searched is empty or only contains white spaces.
+var ErrEmptyAccount = errors.New("the account to be searched is empty")
+
+//ErrInvalidEmail Returned without calling the API when the email given to PasteAccount is not a valid email address.
+var ErrInvalidEmail = errors.New("the email does not comply with an acceptable format")
+
+//ErrNotFound Returned by BreachedAccount and PasteAccount when the account is not found and Client.NotFoundIsError is set.
+var ErrNotFound = errors.New("the account could not be found")
+
+//ErrUnauthorized Wrapped by the error returned when the API answers 401, and returned without calling the API when no API key is configured for a call that needs one. The breaches list, single breach and data classes are public and work without a key.
+var ErrUnauthorized = errors.New("unauthorized")
+
+//ErrForbidden Wrapped by the error returned when the API answers 403, along with the message of the response. It usually means the subscription does not include the service, or the domain searched is not verified.
+var ErrForbidden = errors.New("forbidden")
+
+//ErrNotModified Returned by the conditional lookups when the API answers 304 Not Modified, so there is no fresh data to return.
+var ErrNotModified = errors.New("not modified since the previous lookup")
+
 //BreachModel Each breach contains a number of attributes describing the incident. In the future, these attributes may expand without the API being versioned.
 type BreachModel struct {
 	Name         string   `json:"Name,omitempty"`
@@ -22,7 +48,7 @@ type BreachModel struct {
 	BreachDate   string   `json:"BreachDate,omitempty"`
 	AddedDate    string   `json:"AddedDate,omitempty"`
 	ModifiedDate string   `json:"ModifiedDate,omitempty"`
-	PwnCount     int      `json:"PwnCount,omitempty"`
+	PwnCount     int64    `json:"PwnCount,omitempty"`
 	Description  string   `json:"Description,omitempty"`
 	DataClasses  []string `json:"DataClasses,omitempty"`
 	IsVerified   bool     `json:"IsVerified,omitempty"`
@@ -44,95 +70,324 @@ type PasteModel struct {
 
 //BreachedAccount The most common use of the API is to return a list of all breaches a particular account has been involved in. The API takes a single parameter which is the account to be searched for. The account is not case sensitive and will be trimmed of leading or trailing white spaces. The account should always be URL encoded.
 func BreachedAccount(account, domainFilter string, truncate, unverified bool) ([]BreachModel, error) {
+	return DefaultClient.BreachedAccount(account, domainFilter, truncate, unverified)
+}
 
-	res, err := callService("breachedaccount", account, domainFilter, truncate, unverified)
+//BreachedAccount Same as the package level BreachedAccount, using the settings of c.
+func (c *Client) BreachedAccount(account, domainFilter string, truncate, unverified bool) ([]BreachModel, error) {
+	return c.BreachedAccountContext(context.Background(), account, domainFilter, truncate, unverified)
+}
+
+//BreachedAccountContext Same as BreachedAccount, the request being bound to ctx.
+func (c *Client) BreachedAccountContext(ctx context.Context, account, domainFilter string, truncate, unverified bool) ([]BreachModel, error) {
+	return c.breachedAccount(ctx, account, domainFilter, truncate, unverified)
+}
+
+func (c *Client) breachedAccount(ctx context.Context, account, domainFilter string, truncate, unverified bool) ([]BreachModel, error) {
+	if strings.TrimSpace(account) == "" {
+		return nil, ErrEmptyAccount
+	}
+
+	body, err := c.callService(ctx, "breachedaccount", account, domainFilter, truncate, unverified)
 	if err != nil {
 		return nil, err
 	}
-	if res.StatusCode == http.StatusNotFound {
+	if body == nil {
+		if c.NotFoundIsError {
+			return nil, ErrNotFound
+		}
 		return nil, nil
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
+	return c.decodeBreaches(body)
+}
+
+//TruncatedBreach A breach as returned by a truncated search: only its Name is known. Use Breach or BreachesMap to get its details.
+type TruncatedBreach struct {
+	Name string `json:"Name"`
+}
+
+//BreachedAccountTruncated Same as BreachedAccount with truncate set, returning the type of what the API actually sends so fields missing from the response cannot be read by mistake.
+func (c *Client) BreachedAccountTruncated(account, domainFilter string, unverified bool) ([]TruncatedBreach, error) {
+	return c.BreachedAccountTruncatedContext(context.Background(), account, domainFilter, unverified)
+}
+
+//BreachedAccountTruncatedContext Same as BreachedAccountTruncated, the request being bound to ctx.
+func (c *Client) BreachedAccountTruncatedContext(ctx context.Context, account, domainFilter string, unverified bool) ([]TruncatedBreach, error) {
+	breaches, err := c.breachedAccount(ctx, account, domainFilter, true, unverified)
+	if breaches == nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	breaches := make([]BreachModel, 0)
-	if err := json.Unmarshal(body, &breaches); err != nil {
-		return nil, err
+	truncated := make([]TruncatedBreach, 0, len(breaches))
+	for _, b := range breaches {
+		truncated = append(truncated, TruncatedBreach{Name: b.Name})
 	}
+	return truncated, err
+}
 
-	return breaches, nil
+//IsBreachedOptions Filters deciding which breaches count for IsBreachedWithOptions.
+type IsBreachedOptions struct {
+	//VerifiedOnly Ignores breaches flagged as unverified.
+	VerifiedOnly bool
+	//MinPwnCount Ignores breaches with a PwnCount lower than this.
+	MinPwnCount int64
+}
+
+//IsBreached Reports whether the account has been involved in any breach, including unverified ones.
+func (c *Client) IsBreached(account string) (bool, error) {
+	return c.IsBreachedContext(context.Background(), account)
+}
+
+//IsBreachedContext Same as IsBreached, the request being bound to ctx.
+func (c *Client) IsBreachedContext(ctx context.Context, account string) (bool, error) {
+	return c.IsBreachedWithOptionsContext(ctx, account, IsBreachedOptions{})
+}
+
+//IsBreachedWithOptions Reports whether the account has been involved in at least one breach passing the filters in opts.
+func (c *Client) IsBreachedWithOptions(account string, opts IsBreachedOptions) (bool, error) {
+	return c.IsBreachedWithOptionsContext(context.Background(), account, opts)
+}
+
+//IsBreachedWithOptionsContext Same as IsBreachedWithOptions, the request being bound to ctx.
+func (c *Client) IsBreachedWithOptionsContext(ctx context.Context, account string, opts IsBreachedOptions) (bool, error) {
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, !opts.VerifiedOnly)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, b := range breaches {
+		if opts.VerifiedOnly && !b.IsVerified {
+			continue
+		}
+		if b.PwnCount < opts.MinPwnCount {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
 }
 
 //Breaches Getting all breached sites in the system. A "breach" is an instance of a system having been compromised by an attacker and the data disclosed.
 func Breaches(domainFilter string) ([]BreachModel, error) {
+	return DefaultClient.Breaches(domainFilter)
+}
+
+//Breaches Same as the package level Breaches, using the settings of c.
+func (c *Client) Breaches(domainFilter string) ([]BreachModel, error) {
+	return c.BreachesContext(context.Background(), domainFilter)
+}
+
+//BreachesContext Same as Breaches, the request being bound to ctx.
+func (c *Client) BreachesContext(ctx context.Context, domainFilter string) ([]BreachModel, error) {
+	return c.breaches(ctx, domainFilter)
+}
 
-	res, err := callService("breaches", "", "", false, false)
+//BreachesMap Same as Breaches, keyed by the Name of the breaches, to look up the details of the names returned by a truncated BreachedAccount. See ByName.
+func (c *Client) BreachesMap(domainFilter string) (map[string]BreachModel, error) {
+	return c.BreachesMapContext(context.Background(), domainFilter)
+}
+
+//BreachesMapContext Same as BreachesMap, the request being bound to ctx.
+func (c *Client) BreachesMapContext(ctx context.Context, domainFilter string) (map[string]BreachModel, error) {
+	breaches, err := c.BreachesContext(ctx, domainFilter)
 	if err != nil {
 		return nil, err
 	}
-	if res.StatusCode == http.StatusNotFound {
+	return ByName(breaches), nil
+}
+
+func (c *Client) breaches(ctx context.Context, domainFilter string) ([]BreachModel, error) {
+
+	body, err := c.callService(ctx, "breaches", "", domainFilter, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
 		return nil, nil
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
+	return c.decodeBreaches(body)
+}
+
+//DomainBreachNames Returns only the names of the breaches against the given domain.
+func (c *Client) DomainBreachNames(domain string) ([]string, error) {
+	return c.DomainBreachNamesContext(context.Background(), domain)
+}
+
+//DomainBreachNamesContext Same as DomainBreachNames, the request being bound to ctx.
+func (c *Client) DomainBreachNamesContext(ctx context.Context, domain string) ([]string, error) {
+	breaches, err := c.BreachesContext(ctx, domain)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	breaches := make([]BreachModel, 0)
-	if err := json.Unmarshal(body, &breaches); err != nil {
-		return nil, err
+	names := make([]string, 0, len(breaches))
+	for _, b := range breaches {
+		names = append(names, b.Name)
 	}
+	return names, nil
+}
+
+//ServiceStats Totals derived from the whole list of breaches in the system.
+type ServiceStats struct {
+	Breaches      int
+	PwnedAccounts int64
+}
+
+//ServiceStats Fetches all breaches and computes the total number of breaches and the sum of their PwnCount.
+func (c *Client) ServiceStats() (ServiceStats, error) {
+	return c.ServiceStatsContext(context.Background())
+}
 
-	return breaches, nil
+//ServiceStatsContext Same as ServiceStats, the request being bound to ctx.
+func (c *Client) ServiceStatsContext(ctx context.Context) (ServiceStats, error) {
+	var stats ServiceStats
+
+	breaches, err := c.BreachesContext(ctx, "")
+	if err != nil {
+		return stats, err
+	}
 
+	stats.Breaches = len(breaches)
+	for _, b := range breaches {
+		stats.PwnedAccounts += b.PwnCount
+	}
+	return stats, nil
 }
 
 //Breach Sometimes just a single breach is required and this can be retrieved by the breach "name". This is the stable value which may or may not be the same as the breach "title" (which can change).
 func Breach(name string) (BreachModel, error) {
+	return DefaultClient.Breach(name)
+}
+
+//Breach Same as the package level Breach, using the settings of c.
+func (c *Client) Breach(name string) (BreachModel, error) {
+	return c.BreachContext(context.Background(), name)
+}
+
+//BreachContext Same as Breach, the request being bound to ctx.
+func (c *Client) BreachContext(ctx context.Context, name string) (BreachModel, error) {
 
 	breach := new(BreachModel)
-	res, err := callService("breach", name, "", false, false)
+	body, err := c.callService(ctx, "breach", name, "", false, false)
 	if err != nil {
 		return *breach, err
 	}
-	if res.StatusCode == http.StatusNotFound {
+	if body == nil {
 		return *breach, nil
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
+	if err := json.Unmarshal(body, &breach); err != nil {
 		return *breach, err
 	}
-	defer res.Body.Close()
 
+	return *breach, nil
+}
+
+//BreachIfModified Same as Breach, but sends a conditional request using the ETag and modified date stored on c from the previous lookup of the same name. When nothing changed since then it returns ErrNotModified, so the caller can skip re-processing it.
+func (c *Client) BreachIfModified(name string) (BreachModel, error) {
+	return c.BreachIfModifiedContext(context.Background(), name)
+}
+
+//BreachIfModifiedContext Same as BreachIfModified, the request being bound to ctx.
+func (c *Client) BreachIfModifiedContext(ctx context.Context, name string) (BreachModel, error) {
+
+	var breach BreachModel
+	req, err := c.newRequest(ctx, "breach", name, "", false, false)
+	if err != nil {
+		return breach, err
+	}
+	if v, ok := c.validator(name); ok {
+		if v.etag != "" {
+			req.Header.Set("If-None-Match", v.etag)
+		}
+		if v.lastModified != "" {
+			req.Header.Set("If-Modified-Since", v.lastModified)
+		}
+	}
+
+	res, err := c.do("breach", req)
+	if err != nil {
+		return breach, err
+	}
+	switch res.StatusCode {
+	case http.StatusNotModified:
+		res.Body.Close()
+		return breach, ErrNotModified
+	case http.StatusNotFound:
+		res.Body.Close()
+		c.setValidator(name, validator{})
+		return breach, nil
+	}
+
+	body, err := c.readJSON(res)
+	if err != nil {
+		return breach, err
+	}
 	if err := json.Unmarshal(body, &breach); err != nil {
-		return *breach, err
+		return breach, err
 	}
 
-	return *breach, nil
+	v := validator{etag: res.Header.Get("ETag"), lastModified: res.Header.Get("Last-Modified")}
+	if v.lastModified == "" {
+		if t, err := time.Parse(time.RFC3339, breach.ModifiedDate); err == nil {
+			v.lastModified = t.UTC().Format(http.TimeFormat)
+		}
+	}
+	c.setValidator(name, v)
+
+	return breach, nil
 }
 
-//PasteAccount The API takes a single parameter which is the email address to be searched for. Unlike searching for breaches, usernames that are not email addresses cannot be searched for. The email is not case sensitive and will be trimmed of leading or trailing white spaces. The email should always be URL encoded.
-func PasteAccount(email string) ([]PasteModel, error) {
-	res, err := callService("pasteaccount", email, "", false, false)
+//BreachChanges Fetches the breach named like baseline and returns how its data classes and PwnCount changed since then, to detect updates of its metadata.
+func (c *Client) BreachChanges(baseline BreachModel) (BreachChange, error) {
+	return c.BreachChangesContext(context.Background(), baseline)
+}
+
+//BreachChangesContext Same as BreachChanges, the request being bound to ctx.
+func (c *Client) BreachChangesContext(ctx context.Context, baseline BreachModel) (BreachChange, error) {
+	latest, err := c.BreachContext(ctx, baseline.Name)
 	if err != nil {
-		return nil, err
+		return BreachChange{}, err
 	}
-	if res.StatusCode == http.StatusNotFound {
-		return nil, nil
+	if latest.Name == "" {
+		return BreachChange{}, fmt.Errorf("breach %q not found", baseline.Name)
 	}
+	return DiffBreach(baseline, latest), nil
+}
+
+//PasteAccount The API takes a single parameter which is the email address to be searched for. Unlike searching for breaches, usernames that are not email addresses cannot be searched for. The email is not case sensitive and will be trimmed of leading or trailing white spaces. The email should always be URL encoded.
+func PasteAccount(email string) ([]PasteModel, error) {
+	return DefaultClient.PasteAccount(email)
+}
 
-	body, err := ioutil.ReadAll(res.Body)
+//PasteAccount Same as the package level PasteAccount, using the settings of c.
+func (c *Client) PasteAccount(email string) ([]PasteModel, error) {
+	return c.PasteAccountContext(context.Background(), email)
+}
+
+//PasteAccountContext Same as PasteAccount, the request being bound to ctx.
+func (c *Client) PasteAccountContext(ctx context.Context, email string) ([]PasteModel, error) {
+	if strings.TrimSpace(email) == "" {
+		return nil, ErrEmptyAccount
+	}
+	if !validEmail(email) {
+		return nil, ErrInvalidEmail
+	}
+	body, err := c.callService(ctx, "pasteaccount", email, "", false, false)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	if body == nil {
+		if c.NotFoundIsError {
+			return nil, ErrNotFound
+		}
+		return nil, nil
+	}
 
 	pastes := make([]PasteModel, 0)
 	if err := json.Unmarshal(body, &pastes); err != nil {
@@ -143,47 +398,199 @@ func PasteAccount(email string) ([]PasteModel, error) {
 
 }
 
-func callService(service, account, domainFilter string, truncate, unverified bool) (*http.Response, error) {
-	client := &http.Client{}
+//forbidden Returns ErrForbidden wrapped with the message of the response.
+func (c *Client) forbidden(res *http.Response) error {
+	body, err := c.readBody(res)
+	if err != nil {
+		return ErrForbidden
+	}
+
+	var payload struct {
+		Message string `json:"message"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Message != "" {
+		msg = payload.Message
+	}
+	if msg == "" {
+		return ErrForbidden
+	}
+	return fmt.Errorf("%w: %s", ErrForbidden, msg)
+}
+
+//serviceError Prefixes err with the service and the account it was called for, accounts of people being redacted, so failures of concurrent calls can be told apart. err is wrapped and still matches with errors.Is.
+func serviceError(service, account string, err error) error {
+	if account == "" {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+	if isAccountService(service) {
+		account = redact(account)
+	}
+	return fmt.Errorf("%s %s: %w", service, account, err)
+}
+
+//publicService Reports whether the service can be called without an API key.
+func publicService(service string) bool {
+	switch service {
+	case "breaches", "breach", "dataclasses":
+		return true
+	}
+	return false
+}
+
+//isAccountService Reports whether the service is searched by the account of a person.
+func isAccountService(service string) bool {
+	return service == "breachedaccount" || service == "pasteaccount"
+}
+
+//redact Masks all but the first character of an account, and of the user part of an email address, like "j***@example.com".
+func redact(account string) string {
+	user, domain := account, ""
+	if at := strings.LastIndex(account, "@"); at >= 0 {
+		user, domain = account[:at], account[at:]
+	}
+	if user == "" {
+		return "***" + domain
+	}
+	_, size := utf8.DecodeRuneInString(user)
+	return user[:size] + "***" + domain
+}
+
+//validEmail Reports whether email is a bare email address, as the pasteaccount service expects.
+func validEmail(email string) bool {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+//callService Calls the service and returns the body of the response, or nil when the API answers 404. See send for the caching.
+func (c *Client) callService(ctx context.Context, service, account, domainFilter string, truncate, unverified bool) ([]byte, error) {
+	req, err := c.newRequest(ctx, service, account, domainFilter, truncate, unverified)
+	if err != nil {
+		return nil, serviceError(service, account, err)
+	}
+
+	body, cached, err := c.send(service, req)
+	if err != nil {
+		return nil, serviceError(service, account, err)
+	}
+	if !cached && isAccountService(service) {
+		c.markChecked(account)
+	}
+	return body, nil
+}
+
+//send Sends the request to the service and returns the body of the response, or nil when the API answers 404. The responses of GET requests are served from and stored in Client.Cache when it is set, keyed by URL, a not found result being stored as an empty value. cached reports whether the body was served from the cache.
+func (c *Client) send(service string, req *http.Request) (body []byte, cached bool, err error) {
+	key := req.URL.String()
+	cache := c.Cache
+	if req.Method != http.MethodGet {
+		cache = nil
+	}
+	if cache != nil {
+		if body, ok := cache.Get(key); ok {
+			if len(body) == 0 {
+				return nil, true, nil
+			}
+			return body, true, nil
+		}
+	}
+
+	res, err := c.do(service, req)
+	if err != nil {
+		return nil, false, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		if cache != nil && c.NegativeCacheTTL > 0 {
+			cache.Set(key, []byte{}, c.NegativeCacheTTL)
+		}
+		return nil, false, nil
+	}
+
+	body, err = c.readJSON(res)
+	if err != nil {
+		return nil, false, err
+	}
+	if cache != nil {
+		cache.Set(key, body, c.PositiveCacheTTL)
+	}
+	return body, false, nil
+}
 
-	u, err := url.Parse(API)
+//newRequest Builds the GET request of the service for the account, with the query parameters of the public calls.
+func (c *Client) newRequest(ctx context.Context, service, account, domainFilter string, truncate, unverified bool) (*http.Request, error) {
+	u, err := url.Parse(c.baseURL())
 	if err != nil {
 		return nil, err
 	}
 
+	if c.NormalizeAccounts && isAccountService(service) {
+		account = strings.ToLower(strings.TrimSpace(account))
+	}
 	u.Path += service + "/" + account
 	parameters := url.Values{}
 	if domainFilter != "" {
-		parameters.Add("domain", domainFilter)
+		parameters.Add("domain", toASCII(domainFilter))
 	}
 	if truncate == false {
 		parameters.Add("truncateResponse", "false")
 	}
-	if unverified {
+	if unverified || (c.DefaultIncludeUnverified && service == "breachedaccount") {
 		parameters.Add("includeUnverified", "true")
 	}
 	u.RawQuery = parameters.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	return c.newAPIRequest(ctx, http.MethodGet, u.String(), nil, !publicService(service))
+}
+
+//newAPIRequest Builds a request to the API with the given method and optional body, carrying the User-Agent and the API key when one is configured. It fails with ErrUnauthorized when needsKey is set and there is no key.
+func (c *Client) newAPIRequest(ctx context.Context, method, rawURL string, body io.Reader, needsKey bool) (*http.Request, error) {
+	key := c.apiKey()
+	if key == "" && needsKey {
+		return nil, ErrUnauthorized
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("User-Agent", "Go/1.15")
-	req.Header.Set("hibp-api-key", os.Getenv("HIBP_API_KEY"))
-	res, err := client.Do(req)
+	if key != "" {
+		req.Header.Set(c.apiKeyHeader(), key)
+	}
+	return req, nil
+}
+
+func (c *Client) do(service string, req *http.Request) (*http.Response, error) {
+	ctx, span := c.startSpan(req.Context(), service)
+	defer span.End()
+
+	res, err := c.doHTTP(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.status_code", res.StatusCode)
+	c.trackRateLimit(service, res.Header)
+	c.trackServerTime(res.Header)
 
 	switch res.StatusCode {
 	case http.StatusBadRequest:
-		return nil, errors.New("the account does not comply with an acceptable format")
+		err = errors.New("the account does not comply with an acceptable format")
 	case http.StatusTooManyRequests:
-		return nil, errors.New("too many requests — the rate limit has been exceeded")
+		err = errors.New("too many requests — the rate limit has been exceeded")
 	case http.StatusUnauthorized:
-		return nil, errors.New("valid header `hibp-api-key` required")
+		err = fmt.Errorf("%w: valid header `%s` required", ErrUnauthorized, c.apiKeyHeader())
+	case http.StatusForbidden:
+		err = c.forbidden(res)
 	}
-
 	if err != nil {
+		res.Body.Close()
+		span.RecordError(err)
 		return nil, err
 	}
+
 	return res, nil
 }
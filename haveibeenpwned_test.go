@@ -1,7 +1,10 @@
 package haveibeenpwned
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -114,20 +117,332 @@ func TestInvalidPasteAccount(t *testing.T) {
 	if pastes != nil {
 		t.Errorf("expected no results, got %d", len(pastes))
 	}
-	if err.Error() != errors.New("the account does not comply with an acceptable format").Error() {
-		t.Errorf("expected: the account does not comply with an acceptable format, got: %s", err)
+	if err != ErrInvalidEmail {
+		t.Errorf("expected: %s, got: %s", ErrInvalidEmail, err)
 	}
 }
 
 func TestManyRequests(t *testing.T) {
 	time.Sleep(APIrateLimit * time.Millisecond)
-	_, err := PasteAccount("test")
-	_, err = PasteAccount("test")
-	_, err = PasteAccount("test")
+	_, err := PasteAccount("test@example.com")
+	_, err = PasteAccount("test@example.com")
+	_, err = PasteAccount("test@example.com")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
-	if err.Error() != errors.New("too many requests — the rate limit has been exceeded").Error() {
-		t.Errorf("expected: too many requests — the rate limit has been exceeded, got %s", err)
+	if err.Error() != "pasteaccount t***@example.com: too many requests — the rate limit has been exceeded" {
+		t.Errorf("expected: pasteaccount t***@example.com: too many requests — the rate limit has been exceeded, got %s", err)
+	}
+}
+
+func TestServiceStats(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe","PwnCount":152445165},{"Name":"Yahoo","PwnCount":453427}]`))
+	})
+	stats, err := c.ServiceStats()
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if stats.Breaches != 2 {
+		t.Errorf("expected 2 breaches, got %d", stats.Breaches)
+	}
+	if stats.PwnedAccounts != 152898592 {
+		t.Errorf("expected 152898592 pwned accounts, got %d", stats.PwnedAccounts)
+	}
+}
+
+func TestBreachIfModified(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"Name":"Adobe","ModifiedDate":"2013-12-04T00:00:00Z"}`))
+	})
+
+	breach, err := c.BreachIfModified("Adobe")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if breach.Name != "Adobe" {
+		t.Fatalf("expected a fresh breach, got name %q", breach.Name)
+	}
+
+	breach, err = c.BreachIfModified("Adobe")
+	if !errors.Is(err, ErrNotModified) {
+		t.Errorf("expected ErrNotModified, got %v", err)
+	}
+	if breach.Name != "" {
+		t.Errorf("expected empty breach, got a name: %s", breach.Name)
+	}
+}
+
+func TestEmptyAccount(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+	if _, err := c.BreachedAccount("  ", "", false, false); err != ErrEmptyAccount {
+		t.Errorf("expected ErrEmptyAccount, got %v", err)
+	}
+	if _, err := c.PasteAccount(""); err != ErrEmptyAccount {
+		t.Errorf("expected ErrEmptyAccount, got %v", err)
+	}
+	if _, err := c.PasteAccount("Test <test@example.com>"); err != ErrInvalidEmail {
+		t.Errorf("expected ErrInvalidEmail, got %v", err)
+	}
+}
+
+func TestIsBreachedWithOptions(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Tiny","PwnCount":10,"IsVerified":true},{"Name":"Unverified","PwnCount":5000}]`))
+	})
+
+	breached, err := c.IsBreached("test@example.com")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if !breached {
+		t.Error("expected account to be breached")
+	}
+
+	breached, err = c.IsBreachedWithOptions("test@example.com", IsBreachedOptions{VerifiedOnly: true, MinPwnCount: 1000})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if breached {
+		t.Error("expected no breach to pass the filters")
+	}
+}
+
+func TestNotFoundIsError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	breaches, err := c.BreachedAccount("test@example.com", "", false, false)
+	if err != nil || breaches != nil {
+		t.Fatalf("expected (nil, nil), got (%v, %v)", breaches, err)
+	}
+
+	c.NotFoundIsError = true
+	if _, err := c.BreachedAccount("test@example.com", "", false, false); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := c.PasteAccount("test@example.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestInternationalizedDomainFilter(t *testing.T) {
+	var domain string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		domain = r.URL.Query().Get("domain")
+		w.Write([]byte(`[]`))
+	})
+	if _, err := c.BreachedAccount("test@example.com", "müller.de", false, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if domain != "xn--mller-kva.de" {
+		t.Errorf("expected xn--mller-kva.de, got %s", domain)
+	}
+}
+
+func TestDomainBreachNames(t *testing.T) {
+	var domain string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		domain = r.URL.Query().Get("domain")
+		w.Write([]byte(`[{"Name":"Adobe","Domain":"adobe.com"},{"Name":"AdobeCreative","Domain":"adobe.com"}]`))
+	})
+	names, err := c.DomainBreachNames("adobe.com")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if domain != "adobe.com" {
+		t.Errorf("expected the domain filter to be sent, got %q", domain)
+	}
+	if len(names) != 2 || names[0] != "Adobe" || names[1] != "AdobeCreative" {
+		t.Errorf("expected [Adobe AdobeCreative], got %v", names)
+	}
+}
+
+func TestServiceError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	_, err := c.BreachedAccount("johndoe@example.com", "", true, false)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Error() != "breachedaccount j***@example.com: too many requests — the rate limit has been exceeded" {
+		t.Errorf("unexpected error message: %s", err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		"johndoe@example.com": "j***@example.com",
+		"élodie@example.fr":   "é***@example.fr",
+		"@example.com":        "***@example.com",
+		"用户名":                 "用***",
+	}
+	for in, want := range cases {
+		if got := redact(in); got != want {
+			t.Errorf("redact(%q): expected %s, got %s", in, want, got)
+		}
+	}
+}
+
+func TestForbidden(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"statusCode":403,"message":"Your subscription does not include this service."}`))
+	})
+	err := c.BreachedDomainStream("example.com", func(string, []string) error { return nil })
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err.Error() != "breacheddomain example.com: forbidden: Your subscription does not include this service." {
+		t.Errorf("unexpected error message: %s", err)
+	}
+}
+
+func TestContextVariants(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request with a canceled context, got %s", r.URL.Path)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"BreachedAccountContext": func() error {
+			_, err := c.BreachedAccountContext(ctx, "test@example.com", "", false, false)
+			return err
+		},
+		"BreachesContext": func() error {
+			_, err := c.BreachesContext(ctx, "")
+			return err
+		},
+		"BreachContext": func() error {
+			_, err := c.BreachContext(ctx, "Adobe")
+			return err
+		},
+		"BreachIfModifiedContext": func() error {
+			_, err := c.BreachIfModifiedContext(ctx, "Adobe")
+			return err
+		},
+		"PasteAccountContext": func() error {
+			_, err := c.PasteAccountContext(ctx, "test@example.com")
+			return err
+		},
+		"IsBreachedContext": func() error {
+			_, err := c.IsBreachedContext(ctx, "test@example.com")
+			return err
+		},
+		"BreachesMapContext": func() error {
+			_, err := c.BreachesMapContext(ctx, "")
+			return err
+		},
+		"DomainBreachNamesContext": func() error {
+			_, err := c.DomainBreachNamesContext(ctx, "adobe.com")
+			return err
+		},
+		"ServiceStatsContext": func() error {
+			_, err := c.ServiceStatsContext(ctx)
+			return err
+		},
+		"BreachChangesContext": func() error {
+			_, err := c.BreachChangesContext(ctx, BreachModel{Name: "Adobe"})
+			return err
+		},
+		"BreachExistsContext": func() error {
+			_, err := c.BreachExistsContext(ctx, "Adobe")
+			return err
+		},
+		"BreachesWithDataClassContext": func() error {
+			_, err := c.BreachesWithDataClassContext(ctx, DataClassPasswords)
+			return err
+		},
+		"BreachesMinPwnCountContext": func() error {
+			_, err := c.BreachesMinPwnCountContext(ctx, 1)
+			return err
+		},
+		"DataClassHistogramContext": func() error {
+			_, err := c.DataClassHistogramContext(ctx, nil)
+			return err
+		},
+		"AccountBreachReportContext": func() error {
+			_, err := c.AccountBreachReportContext(ctx, "test@example.com")
+			return err
+		},
+		"RelevantBreachesContext": func() error {
+			_, err := c.RelevantBreachesContext(ctx, "test@example.com", nil)
+			return err
+		},
+		"BreachedAccountBetweenContext": func() error {
+			_, err := c.BreachedAccountBetweenContext(ctx, "test@example.com", time.Time{}, time.Now())
+			return err
+		},
+		"NewBreachesSinceContext": func() error {
+			_, err := c.NewBreachesSinceContext(ctx, "test@example.com", nil)
+			return err
+		},
+		"InSensitiveBreachContext": func() error {
+			_, err := c.InSensitiveBreachContext(ctx, "test@example.com")
+			return err
+		},
+		"AccountContext": func() error {
+			_, err := c.AccountContext(ctx, "test@example.com")
+			return err
+		},
+		"SaveBreachesContext": func() error {
+			return c.SaveBreachesContext(ctx, filepath.Join(t.TempDir(), "breaches.json"))
+		},
+		"BreachedDomainStreamContext": func() error {
+			return c.BreachedDomainStreamContext(ctx, "example.com", func(string, []string) error { return nil })
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
+
+func TestBreachedAccountTruncated(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Forum"}]`))
+	})
+
+	breaches, err := c.BreachedAccountTruncated("test@example.com", "", false)
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if query != "" {
+		t.Errorf("expected a truncated request, got %q", query)
+	}
+	if len(breaches) != 2 || breaches[0] != (TruncatedBreach{Name: "Adobe"}) {
+		t.Errorf("unexpected breaches: %v", breaches)
+	}
+}
+
+func TestDefaultIncludeUnverified(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`[]`))
+	})
+	c.DefaultIncludeUnverified = true
+
+	if _, err := c.BreachedAccount("test@example.com", "", true, false); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if _, err := c.Breaches(""); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(queries) != 2 || queries[0] != "includeUnverified=true" || queries[1] != "truncateResponse=false" {
+		t.Errorf("expected unverified breaches requested for the account only, got %v", queries)
 	}
 }
@@ -0,0 +1,283 @@
+package haveibeenpwned
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+//BreachDateLayout Layout of the BreachDate of a breach, for time.Parse.
+const BreachDateLayout = "2006-01-02"
+
+//Safe Reports whether the breach can be shown in a public context. Sensitive breaches and spam lists are hidden from public searches by HIBP itself.
+func (b BreachModel) Safe() bool {
+	return !b.IsSensitive && !b.IsSpamList
+}
+
+//IsSpam Reports whether the breach is a spam list, data gathered for marketing or spam rather than a real compromise of a service.
+func (b BreachModel) IsSpam() bool {
+	return b.IsSpamList
+}
+
+//Exposed Reports whether the breach exposed the given data class, compared case insensitively.
+func (b BreachModel) Exposed(class string) bool {
+	for _, dc := range b.DataClasses {
+		if strings.EqualFold(dc, class) {
+			return true
+		}
+	}
+	return false
+}
+
+//FilterSafe Returns only the breaches that are Safe to be displayed publicly. The input slice is not modified.
+func FilterSafe(breaches []BreachModel) []BreachModel {
+	safe := make([]BreachModel, 0, len(breaches))
+	for _, b := range breaches {
+		if b.Safe() {
+			safe = append(safe, b)
+		}
+	}
+	return safe
+}
+
+//Active Reports whether the breach is still considered valid. Retired breaches are no longer relevant to report.
+func (b BreachModel) Active() bool {
+	return !b.IsRetired
+}
+
+//FilterActive Returns only the breaches that are Active. The input slice is not modified.
+func FilterActive(breaches []BreachModel) []BreachModel {
+	active := make([]BreachModel, 0, len(breaches))
+	for _, b := range breaches {
+		if b.Active() {
+			active = append(active, b)
+		}
+	}
+	return active
+}
+
+//DiffBreaches Compares two lists of breaches keyed by Name. Added are the breaches only present in latest, removed the ones only present in old, and modified the ones of latest whose ModifiedDate is a different instant than the one in old.
+func DiffBreaches(old, latest []BreachModel) (added, removed, modified []BreachModel) {
+	previous := make(map[string]BreachModel, len(old))
+	for _, b := range old {
+		previous[b.Name] = b
+	}
+	current := make(map[string]bool, len(latest))
+
+	for _, b := range latest {
+		current[b.Name] = true
+		p, ok := previous[b.Name]
+		switch {
+		case !ok:
+			added = append(added, b)
+		case !sameModifiedDate(p.ModifiedDate, b.ModifiedDate):
+			modified = append(modified, b)
+		}
+	}
+	for _, b := range old {
+		if !current[b.Name] {
+			removed = append(removed, b)
+		}
+	}
+	return added, removed, modified
+}
+
+//sameModifiedDate Reports whether two modified dates are the same instant, whatever their time zone or precision, comparing them as strings when either cannot be parsed.
+func sameModifiedDate(a, b string) bool {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ta.Equal(tb)
+}
+
+//BreachChange Differences in the metadata of a breach between a baseline and its latest version.
+type BreachChange struct {
+	Name               string
+	AddedDataClasses   []string
+	RemovedDataClasses []string
+	OldPwnCount        int64
+	NewPwnCount        int64
+}
+
+//Changed Reports whether any data class or the PwnCount changed.
+func (ch BreachChange) Changed() bool {
+	return len(ch.AddedDataClasses) > 0 || len(ch.RemovedDataClasses) > 0 || ch.OldPwnCount != ch.NewPwnCount
+}
+
+//DiffBreach Compares the DataClasses and PwnCount of the latest version of a breach to the ones of baseline.
+func DiffBreach(baseline, latest BreachModel) BreachChange {
+	ch := BreachChange{Name: latest.Name, OldPwnCount: baseline.PwnCount, NewPwnCount: latest.PwnCount}
+	for _, dc := range latest.DataClasses {
+		if !baseline.Exposed(dc) {
+			ch.AddedDataClasses = append(ch.AddedDataClasses, dc)
+		}
+	}
+	for _, dc := range baseline.DataClasses {
+		if !latest.Exposed(dc) {
+			ch.RemovedDataClasses = append(ch.RemovedDataClasses, dc)
+		}
+	}
+	return ch
+}
+
+//GroupByDomain Groups the breaches by their Domain. Breaches without a domain are grouped under the "" key.
+func GroupByDomain(breaches []BreachModel) map[string][]BreachModel {
+	groups := make(map[string][]BreachModel)
+	for _, b := range breaches {
+		groups[b.Domain] = append(groups[b.Domain], b)
+	}
+	return groups
+}
+
+//ByName Keys the breaches by their Name. Names are unique in HIBP, should two breaches share one anyway the last wins.
+func ByName(breaches []BreachModel) map[string]BreachModel {
+	byName := make(map[string]BreachModel, len(breaches))
+	for _, b := range breaches {
+		byName[b.Name] = b
+	}
+	return byName
+}
+
+//ToMap Flattens the breach into a map keyed by the JSON field names, for templates and structured loggers. DataClasses is joined into a single comma separated string.
+func (b BreachModel) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"Name":         b.Name,
+		"Title":        b.Title,
+		"Domain":       b.Domain,
+		"BreachDate":   b.BreachDate,
+		"AddedDate":    b.AddedDate,
+		"ModifiedDate": b.ModifiedDate,
+		"PwnCount":     b.PwnCount,
+		"Description":  b.Description,
+		"DataClasses":  strings.Join(b.DataClasses, ", "),
+		"IsVerified":   b.IsVerified,
+		"IsFabricated": b.IsFabricated,
+		"IsSensitive":  b.IsSensitive,
+		"IsRetired":    b.IsRetired,
+		"IsSpamList":   b.IsSpamList,
+		"LogoPath":     b.LogoPath,
+	}
+}
+
+//DateRange Returns the earliest and latest BreachDate of the breaches. Breaches whose date cannot be parsed are skipped, and an error is returned if none can.
+func DateRange(breaches []BreachModel) (earliest, latest time.Time, err error) {
+	for _, b := range breaches {
+		date, err := time.Parse(BreachDateLayout, b.BreachDate)
+		if err != nil {
+			continue
+		}
+		if earliest.IsZero() || date.Before(earliest) {
+			earliest = date
+		}
+		if latest.IsZero() || date.After(latest) {
+			latest = date
+		}
+	}
+	if earliest.IsZero() {
+		return earliest, latest, errors.New("no breach with a valid BreachDate")
+	}
+	return earliest, latest, nil
+}
+
+//BreachedBetween Returns the breaches whose BreachDate falls between from and to, both included. Breaches whose date cannot be parsed are skipped. The input slice is not modified.
+func BreachedBetween(breaches []BreachModel, from, to time.Time) []BreachModel {
+	between := make([]BreachModel, 0)
+	for _, b := range breaches {
+		date, err := time.Parse(BreachDateLayout, b.BreachDate)
+		if err != nil {
+			continue
+		}
+		if !date.Before(from) && !date.After(to) {
+			between = append(between, b)
+		}
+	}
+	return between
+}
+
+//SortByPwnCount Sorts the breaches in place by PwnCount, biggest first unless ascending is set. Truncated results have no PwnCount, those zero counts are always sorted to the end. The sort is stable.
+func SortByPwnCount(breaches []BreachModel, ascending bool) {
+	sort.SliceStable(breaches, func(i, j int) bool {
+		a, b := breaches[i].PwnCount, breaches[j].PwnCount
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		if ascending {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+//SortPastesByDate Sorts the pastes in place by Date, newest first unless ascending is set. Some sources do not date their pastes, those and the ones whose Date cannot be parsed are always sorted to the end. The sort is stable.
+func SortPastesByDate(pastes []PasteModel, ascending bool) {
+	sort.SliceStable(pastes, func(i, j int) bool {
+		a, errA := time.Parse(time.RFC3339, pastes[i].Date)
+		b, errB := time.Parse(time.RFC3339, pastes[j].Date)
+		if errA != nil || errB != nil {
+			return errB != nil && errA == nil
+		}
+		if ascending {
+			return a.Before(b)
+		}
+		return a.After(b)
+	})
+}
+
+//TotalPasteEmailCount Returns the sum of the EmailCount of the pastes, how many email addresses appeared alongside the searched one.
+func TotalPasteEmailCount(pastes []PasteModel) int {
+	total := 0
+	for _, p := range pastes {
+		total += p.EmailCount
+	}
+	return total
+}
+
+//DiffDomainBreaches Compares two results of a domain search, mapping aliases to breach names as given to BreachedDomainStream. It returns, for each alias of latest, the breaches not listed for it in old. Aliases newly affected come with all their breaches, and aliases with nothing new are left out.
+func DiffDomainBreaches(old, latest map[string][]string) map[string][]string {
+	diff := make(map[string][]string)
+	for alias, breaches := range latest {
+		known := make(map[string]bool, len(old[alias]))
+		for _, name := range old[alias] {
+			known[name] = true
+		}
+		for _, name := range breaches {
+			if !known[name] {
+				diff[alias] = append(diff[alias], name)
+			}
+		}
+	}
+	return diff
+}
+
+//NewerThan Reports whether the ModifiedDate of remote is later than the one of local. A local copy without a modified date is older than any dated remote, and a remote without one is never newer.
+func (remote BreachModel) NewerThan(local BreachModel) (bool, error) {
+	if remote.ModifiedDate == "" {
+		return false, nil
+	}
+	r, err := time.Parse(time.RFC3339, remote.ModifiedDate)
+	if err != nil {
+		return false, err
+	}
+	if local.ModifiedDate == "" {
+		return true, nil
+	}
+	l, err := time.Parse(time.RFC3339, local.ModifiedDate)
+	if err != nil {
+		return false, err
+	}
+	return r.After(l), nil
+}
+
+//DistinctDomains Counts the distinct companies the breaches happened at, that is the distinct non empty Domain values. Several breaches of the same company count once, and breaches without a domain are not counted.
+func DistinctDomains(breaches []BreachModel) int {
+	domains := make(map[string]bool)
+	for _, b := range breaches {
+		if b.Domain != "" {
+			domains[strings.ToLower(b.Domain)] = true
+		}
+	}
+	return len(domains)
+}
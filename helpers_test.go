@@ -0,0 +1,266 @@
+package haveibeenpwned
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterSafe(t *testing.T) {
+	breaches := []BreachModel{
+		{Name: "Adobe"},
+		{Name: "Ashley", IsSensitive: true},
+		{Name: "Spam", IsSpamList: true},
+	}
+	safe := FilterSafe(breaches)
+	if len(safe) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(safe))
+	}
+	if safe[0].Name != "Adobe" {
+		t.Errorf("expected Adobe, got %s", safe[0].Name)
+	}
+}
+
+func TestFilterActive(t *testing.T) {
+	breaches := []BreachModel{
+		{Name: "Adobe"},
+		{Name: "Retired", IsRetired: true},
+	}
+	active := FilterActive(breaches)
+	if len(active) != 1 || active[0].Name != "Adobe" {
+		t.Errorf("expected Adobe only, got %v", active)
+	}
+}
+
+func TestDiffBreaches(t *testing.T) {
+	old := []BreachModel{
+		{Name: "Adobe", ModifiedDate: "2013-12-04T00:00:00Z"},
+		{Name: "Yahoo", ModifiedDate: "2016-12-14T00:00:00Z"},
+		{Name: "Gone", ModifiedDate: "2015-01-01T00:00:00Z"},
+		{Name: "Canva", ModifiedDate: "2019-05-24T10:00:00Z"},
+		{Name: "Undated", ModifiedDate: "unknown"},
+	}
+	latest := []BreachModel{
+		{Name: "Adobe", ModifiedDate: "2013-12-04T00:00:00Z"},
+		{Name: "Canva", ModifiedDate: "2019-05-24T12:00:00.000+02:00"},
+		{Name: "Undated", ModifiedDate: "unknown"},
+		{Name: "Yahoo", ModifiedDate: "2017-01-01T00:00:00Z"},
+		{Name: "New", ModifiedDate: "2020-01-01T00:00:00Z"},
+	}
+	added, removed, modified := DiffBreaches(old, latest)
+	if len(added) != 1 || added[0].Name != "New" {
+		t.Errorf("expected New to be added, got %v", added)
+	}
+	if len(removed) != 1 || removed[0].Name != "Gone" {
+		t.Errorf("expected Gone to be removed, got %v", removed)
+	}
+	if len(modified) != 1 || modified[0].Name != "Yahoo" {
+		t.Errorf("expected Yahoo to be modified, got %v", modified)
+	}
+}
+
+func TestDiffBreach(t *testing.T) {
+	baseline := BreachModel{Name: "Adobe", PwnCount: 100, DataClasses: []string{DataClassEmailAddresses, DataClassPasswordHints}}
+	latest := BreachModel{Name: "Adobe", PwnCount: 150, DataClasses: []string{DataClassEmailAddresses, DataClassPasswords}}
+
+	ch := DiffBreach(baseline, latest)
+	if !ch.Changed() {
+		t.Fatal("expected the breach to be changed")
+	}
+	if len(ch.AddedDataClasses) != 1 || ch.AddedDataClasses[0] != DataClassPasswords {
+		t.Errorf("expected Passwords to be added, got %v", ch.AddedDataClasses)
+	}
+	if len(ch.RemovedDataClasses) != 1 || ch.RemovedDataClasses[0] != DataClassPasswordHints {
+		t.Errorf("expected Password hints to be removed, got %v", ch.RemovedDataClasses)
+	}
+	if ch.OldPwnCount != 100 || ch.NewPwnCount != 150 {
+		t.Errorf("unexpected counts: %d -> %d", ch.OldPwnCount, ch.NewPwnCount)
+	}
+
+	if DiffBreach(latest, latest).Changed() {
+		t.Error("expected no change against itself")
+	}
+}
+
+func TestGroupByDomain(t *testing.T) {
+	groups := GroupByDomain([]BreachModel{
+		{Name: "Yahoo", Domain: "yahoo.com"},
+		{Name: "YahooVoices", Domain: "yahoo.com"},
+		{Name: "Collection1"},
+	})
+	if len(groups["yahoo.com"]) != 2 {
+		t.Errorf("expected 2 breaches for yahoo.com, got %d", len(groups["yahoo.com"]))
+	}
+	if len(groups[""]) != 1 {
+		t.Errorf("expected 1 breach without domain, got %d", len(groups[""]))
+	}
+}
+
+func TestByName(t *testing.T) {
+	byName := ByName([]BreachModel{
+		{Name: "Adobe", PwnCount: 1},
+		{Name: "Forum"},
+		{Name: "Adobe", PwnCount: 2},
+	})
+	if len(byName) != 2 || byName["Adobe"].PwnCount != 2 {
+		t.Errorf("expected 2 breaches with the last Adobe winning, got %v", byName)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	m := BreachModel{Name: "Adobe", PwnCount: 152445165, DataClasses: []string{"Email addresses", "Passwords"}}.ToMap()
+	if m["Name"] != "Adobe" {
+		t.Errorf("expected Adobe, got %v", m["Name"])
+	}
+	if m["DataClasses"] != "Email addresses, Passwords" {
+		t.Errorf("expected joined data classes, got %v", m["DataClasses"])
+	}
+	if len(m) != 15 {
+		t.Errorf("expected 15 keys, got %d", len(m))
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	earliest, latest, err := DateRange([]BreachModel{
+		{Name: "Yahoo", BreachDate: "2016-12-14"},
+		{Name: "Adobe", BreachDate: "2013-10-04"},
+		{Name: "Broken", BreachDate: "unknown"},
+		{Name: "Canva", BreachDate: "2019-05-24"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if earliest.Format(BreachDateLayout) != "2013-10-04" {
+		t.Errorf("expected 2013-10-04, got %s", earliest.Format(BreachDateLayout))
+	}
+	if latest.Format(BreachDateLayout) != "2019-05-24" {
+		t.Errorf("expected 2019-05-24, got %s", latest.Format(BreachDateLayout))
+	}
+
+	if _, _, err := DateRange([]BreachModel{{Name: "Broken"}}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestBreachedBetween(t *testing.T) {
+	breaches := []BreachModel{
+		{Name: "Before", BreachDate: "2022-12-31"},
+		{Name: "First", BreachDate: "2023-01-01"},
+		{Name: "Undated"},
+		{Name: "Last", BreachDate: "2023-12-31"},
+		{Name: "After", BreachDate: "2024-01-01"},
+	}
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	between := BreachedBetween(breaches, from, to)
+	if len(between) != 2 || between[0].Name != "First" || between[1].Name != "Last" {
+		t.Errorf("expected First and Last, got %v", between)
+	}
+}
+
+func TestSortByPwnCount(t *testing.T) {
+	breaches := []BreachModel{
+		{Name: "Truncated"},
+		{Name: "Small", PwnCount: 10},
+		{Name: "Big", PwnCount: 1000},
+	}
+
+	SortByPwnCount(breaches, false)
+	if breaches[0].Name != "Big" || breaches[1].Name != "Small" || breaches[2].Name != "Truncated" {
+		t.Errorf("unexpected descending order: %v", breaches)
+	}
+
+	SortByPwnCount(breaches, true)
+	if breaches[0].Name != "Small" || breaches[1].Name != "Big" || breaches[2].Name != "Truncated" {
+		t.Errorf("unexpected ascending order: %v", breaches)
+	}
+}
+
+func TestSortPastesByDate(t *testing.T) {
+	pastes := []PasteModel{
+		{ID: "Undated"},
+		{ID: "Old", Date: "2014-03-04T19:14:54Z"},
+		{ID: "New", Date: "2019-11-20T08:02:11Z"},
+	}
+
+	SortPastesByDate(pastes, false)
+	if pastes[0].ID != "New" || pastes[1].ID != "Old" || pastes[2].ID != "Undated" {
+		t.Errorf("unexpected descending order: %v", pastes)
+	}
+
+	SortPastesByDate(pastes, true)
+	if pastes[0].ID != "Old" || pastes[1].ID != "New" || pastes[2].ID != "Undated" {
+		t.Errorf("unexpected ascending order: %v", pastes)
+	}
+}
+
+func TestTotalPasteEmailCount(t *testing.T) {
+	pastes := []PasteModel{{EmailCount: 3}, {}, {EmailCount: 139}}
+	if total := TotalPasteEmailCount(pastes); total != 142 {
+		t.Errorf("expected 142, got %d", total)
+	}
+}
+
+func TestDiffDomainBreaches(t *testing.T) {
+	old := map[string][]string{
+		"alias1": {"Adobe"},
+		"alias2": {"Adobe"},
+	}
+	latest := map[string][]string{
+		"alias1": {"Adobe"},
+		"alias2": {"Adobe", "Canva"},
+		"alias3": {"Canva"},
+	}
+	diff := DiffDomainBreaches(old, latest)
+	if len(diff) != 2 {
+		t.Fatalf("expected 2 aliases, got %v", diff)
+	}
+	if len(diff["alias2"]) != 1 || diff["alias2"][0] != "Canva" {
+		t.Errorf("expected Canva to be new for alias2, got %v", diff["alias2"])
+	}
+	if len(diff["alias3"]) != 1 || diff["alias3"][0] != "Canva" {
+		t.Errorf("expected Canva to be new for alias3, got %v", diff["alias3"])
+	}
+}
+
+func TestNewerThan(t *testing.T) {
+	older := BreachModel{Name: "Adobe", ModifiedDate: "2013-12-04T00:00:00Z"}
+	newer := BreachModel{Name: "Adobe", ModifiedDate: "2022-05-15T23:52:49Z"}
+	undated := BreachModel{Name: "Adobe"}
+
+	tests := []struct {
+		remote, local BreachModel
+		expected      bool
+	}{
+		{newer, older, true},
+		{older, newer, false},
+		{older, older, false},
+		{older, undated, true},
+		{undated, older, false},
+	}
+	for _, tt := range tests {
+		newer, err := tt.remote.NewerThan(tt.local)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if newer != tt.expected {
+			t.Errorf("%q newer than %q: expected %v", tt.remote.ModifiedDate, tt.local.ModifiedDate, tt.expected)
+		}
+	}
+
+	if _, err := (BreachModel{ModifiedDate: "yesterday"}).NewerThan(older); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestDistinctDomains(t *testing.T) {
+	count := DistinctDomains([]BreachModel{
+		{Name: "Yahoo", Domain: "yahoo.com"},
+		{Name: "YahooVoices", Domain: "Yahoo.com"},
+		{Name: "Adobe", Domain: "adobe.com"},
+		{Name: "Collection1"},
+	})
+	if count != 2 {
+		t.Errorf("expected 2 domains, got %d", count)
+	}
+}
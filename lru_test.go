@@ -0,0 +1,21 @@
+package haveibeenpwned
+
+import "testing"
+
+func TestLRUEviction(t *testing.T) {
+	l := newLRU(2)
+	l.set("a", 1)
+	l.set("b", 2)
+	l.get("a")
+	l.set("c", 3)
+
+	if _, ok := l.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if v, ok := l.get("a"); !ok || v != 1 {
+		t.Errorf("expected a to be kept, got %v", v)
+	}
+	if v, ok := l.get("c"); !ok || v != 3 {
+		t.Errorf("expected c to be kept, got %v", v)
+	}
+}
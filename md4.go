@@ -0,0 +1,55 @@
+package haveibeenpwned
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+var (
+	md4Shift1 = [4]int{3, 7, 11, 19}
+	md4Shift2 = [4]int{3, 5, 9, 13}
+	md4Shift3 = [4]int{3, 9, 11, 15}
+	md4Order2 = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	md4Order3 = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+)
+
+//md4Sum Returns the MD4 digest (RFC 1320) of msg. MD4 is broken and only kept here because NTLM hashes, and so the NTLM mode of the range API, are built on it.
+func md4Sum(msg []byte) [16]byte {
+	bitLen := uint64(len(msg)) * 8
+	padded := append(append([]byte{}, msg...), 0x80)
+	for len(padded)%64 != 56 {
+		padded = append(padded, 0)
+	}
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], bitLen)
+	padded = append(padded, length[:]...)
+
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+	for i := 0; i < len(padded); i += 64 {
+		var x [16]uint32
+		for j := range x {
+			x[j] = binary.LittleEndian.Uint32(padded[i+4*j:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+		for j := 0; j < 16; j++ {
+			t := a + (b&c | ^b&d) + x[j]
+			a, b, c, d = d, bits.RotateLeft32(t, md4Shift1[j%4]), b, c
+		}
+		for j := 0; j < 16; j++ {
+			t := a + (b&c | b&d | c&d) + x[md4Order2[j]] + 0x5a827999
+			a, b, c, d = d, bits.RotateLeft32(t, md4Shift2[j%4]), b, c
+		}
+		for j := 0; j < 16; j++ {
+			t := a + (b ^ c ^ d) + x[md4Order3[j]] + 0x6ed9eba1
+			a, b, c, d = d, bits.RotateLeft32(t, md4Shift3[j%4]), b, c
+		}
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	var sum [16]byte
+	binary.LittleEndian.PutUint32(sum[0:], a)
+	binary.LittleEndian.PutUint32(sum[4:], b)
+	binary.LittleEndian.PutUint32(sum[8:], c)
+	binary.LittleEndian.PutUint32(sum[12:], d)
+	return sum
+}
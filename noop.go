@@ -0,0 +1,55 @@
+package haveibeenpwned
+
+//Service Lookups shared by Client and NoopClient, so callers can depend on it and swap the implementation, like disabling the integration at runtime.
+type Service interface {
+	BreachedAccount(account, domainFilter string, truncate, unverified bool) ([]BreachModel, error)
+	IsBreached(account string) (bool, error)
+	Breaches(domainFilter string) ([]BreachModel, error)
+	Breach(name string) (BreachModel, error)
+	PasteAccount(email string) ([]PasteModel, error)
+	PwnedPassword(password string) (int, error)
+	IsPasswordPwned(password string) (bool, error)
+}
+
+var (
+	_ Service = (*Client)(nil)
+	_ Service = NoopClient{}
+)
+
+//NoopClient Service calling nothing and returning empty results without errors, for environments where the integration is disabled. It always reports accounts and passwords as not pwned.
+type NoopClient struct{}
+
+//BreachedAccount Returns no breaches.
+func (NoopClient) BreachedAccount(account, domainFilter string, truncate, unverified bool) ([]BreachModel, error) {
+	return []BreachModel{}, nil
+}
+
+//IsBreached Returns false.
+func (NoopClient) IsBreached(account string) (bool, error) {
+	return false, nil
+}
+
+//Breaches Returns no breaches.
+func (NoopClient) Breaches(domainFilter string) ([]BreachModel, error) {
+	return []BreachModel{}, nil
+}
+
+//Breach Returns an empty BreachModel, as for an unknown name.
+func (NoopClient) Breach(name string) (BreachModel, error) {
+	return BreachModel{}, nil
+}
+
+//PasteAccount Returns no pastes.
+func (NoopClient) PasteAccount(email string) ([]PasteModel, error) {
+	return []PasteModel{}, nil
+}
+
+//PwnedPassword Returns 0.
+func (NoopClient) PwnedPassword(password string) (int, error) {
+	return 0, nil
+}
+
+//IsPasswordPwned Returns false.
+func (NoopClient) IsPasswordPwned(password string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,17 @@
+package haveibeenpwned
+
+import "testing"
+
+func TestNoopClient(t *testing.T) {
+	var s Service = NoopClient{}
+
+	if pwned, err := s.IsBreached("test@example.com"); pwned || err != nil {
+		t.Errorf("expected not breached without error, got %v and %v", pwned, err)
+	}
+	if breaches, err := s.BreachedAccount("test@example.com", "", false, false); breaches == nil || len(breaches) != 0 || err != nil {
+		t.Errorf("expected an empty result without error, got %v and %v", breaches, err)
+	}
+	if pwned, err := s.IsPasswordPwned("password"); pwned || err != nil {
+		t.Errorf("expected not pwned without error, got %v and %v", pwned, err)
+	}
+}
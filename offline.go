@@ -0,0 +1,91 @@
+package haveibeenpwned
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//OfflineRange Same as PasswordRange, but reads the range from the downloaded Pwned Passwords file at Client.OfflinePasswordsFile instead of calling the API. The file must hold one "HASH:COUNT" line per hash, ordered by hash, so the block of the prefix is found with a binary search.
+func (c *Client) OfflineRange(prefix string) (map[string]int, error) {
+	if !validPrefix(prefix) {
+		return nil, fmt.Errorf("invalid hash prefix %q, expected 5 hexadecimal characters", prefix)
+	}
+	prefix = strings.ToUpper(prefix)
+
+	f, err := os.Open(c.OfflinePasswordsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	// smallest offset whose following line is not before the prefix
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		_, line, err := lineAt(f, size, mid)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" || strings.ToUpper(line) >= prefix {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	start, _, err := lineAt(f, size, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := make(map[string]int)
+	scanner := bufio.NewScanner(io.NewSectionReader(f, start, size-start))
+	for scanner.Scan() {
+		line := strings.ToUpper(strings.TrimSpace(scanner.Text()))
+		if !strings.HasPrefix(line, prefix) {
+			break
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed line %q", line)
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed line %q: %v", line, err)
+		}
+		suffixes[parts[0][len(prefix):]] = count
+	}
+	return suffixes, scanner.Err()
+}
+
+//lineAt Returns the offset and the content of the first line starting at or after off, or an empty line past the end of the file.
+func lineAt(r io.ReaderAt, size, off int64) (int64, string, error) {
+	start := int64(0)
+	br := bufio.NewReader(io.NewSectionReader(r, 0, size))
+	if off > 0 {
+		br = bufio.NewReader(io.NewSectionReader(r, off-1, size-off+1))
+		skipped, err := br.ReadString('\n')
+		if err == io.EOF {
+			return size, "", nil
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		start = off - 1 + int64(len(skipped))
+	}
+
+	line, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+	return start, strings.TrimRight(line, "\r\n"), nil
+}
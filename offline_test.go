@@ -0,0 +1,52 @@
+package haveibeenpwned
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOfflineRange(t *testing.T) {
+	lines := "00000A8DA2B8DD4AC3F85856C8F20DC6433:2\r\n" +
+		"5BAA50000000000000000000000000000000:1\r\n" +
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n" +
+		"5BAA6F1D3A2B8AA5A9D9D2E70A6C7A4F0D7E2A11:3\r\n" +
+		"5BAA70000000000000000000000000000000:7\r\n" +
+		"FFFFFFF8A0382AA9C8D9536EFBA77F261815334D:12\r\n"
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	if err := os.WriteFile(path, []byte(lines), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{OfflinePasswordsFile: path}
+
+	suffixes, err := c.OfflineRange("5baa6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]int{
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD8": 9545824,
+		"F1D3A2B8AA5A9D9D2E70A6C7A4F0D7E2A11": 3,
+	}
+	if !reflect.DeepEqual(suffixes, expected) {
+		t.Errorf("expected %v, got %v", expected, suffixes)
+	}
+
+	for _, prefix := range []string{"00000", "FFFFF"} {
+		suffixes, err := c.OfflineRange(prefix)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(suffixes) != 1 {
+			t.Errorf("%s: expected 1 result, got %v", prefix, suffixes)
+		}
+	}
+
+	suffixes, err = c.OfflineRange("ABCDE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suffixes) != 0 {
+		t.Errorf("expected no results, got %v", suffixes)
+	}
+}
@@ -0,0 +1,58 @@
+package haveibeenpwned
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+//Option Configures a Client built with NewClient.
+type Option func(*Client)
+
+//WithInsecureSkipVerify Disables the verification of the server certificate.
+//
+//WARNING: this is meant for tests against a local server, like the ones started by httptest.NewTLSServer. Never use it in production: it makes the connection vulnerable to man-in-the-middle attacks, leaking the API key and every account searched.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		t := c.transport()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+}
+
+//WithDialTimeout Limits the time spent connecting to the API, independently of the time the API takes to answer.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.transport().DialContext = (&net.Dialer{Timeout: d, KeepAlive: 30 * time.Second}).DialContext
+	}
+}
+
+//WithResponseHeaderTimeout Limits the time waiting for the headers of the response once the request is sent. It does not include the time spent connecting nor reading the body.
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.transport().ResponseHeaderTimeout = d
+	}
+}
+
+//WithClock Replaces time.Now wherever the client reads the time, like the expiration of BreachesCacheTTL or the Age of the reports of AccountBreachReport, so tests can control it without sleeping. It does not reach MemoryCache, which has its own Clock, nor the package level NewBreachReport and RiskWeights.Score.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.clock = now
+	}
+}
+
+//transport Returns the *http.Transport of c.HTTPClient so options can tune it, creating both from the defaults when missing.
+func (c *Client) transport() *http.Transport {
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{}
+	}
+	t, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t = http.DefaultTransport.(*http.Transport).Clone()
+		c.HTTPClient.Transport = t
+	}
+	return t
+}
@@ -0,0 +1,78 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Name":"Adobe"}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL + "/"}
+	if _, err := c.Breach("Adobe"); err == nil {
+		t.Fatal("expected a certificate error, got nil")
+	}
+
+	c = NewClient(WithInsecureSkipVerify())
+	c.BaseURL = server.URL + "/"
+	breach, err := c.Breach("Adobe")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if breach.Name != "Adobe" {
+		t.Errorf("expected Adobe, got %s", breach.Name)
+	}
+}
+
+func TestWithResponseHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Name":"Adobe"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithDialTimeout(time.Second), WithResponseHeaderTimeout(10*time.Millisecond))
+	c.BaseURL = server.URL + "/"
+	if _, err := c.Breach("Adobe"); err == nil {
+		t.Error("expected a timeout error, got nil")
+	}
+
+	c = NewClient(WithDialTimeout(time.Second), WithResponseHeaderTimeout(time.Second))
+	c.BaseURL = server.URL + "/"
+	if _, err := c.Breach("Adobe"); err != nil {
+		t.Errorf("response error: %v", err)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"Name":"Adobe"}]`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient(WithClock(func() time.Time { return now }))
+	c.BaseURL = server.URL + "/"
+	c.BreachesCacheTTL = time.Hour
+
+	for _, elapsed := range []time.Duration{0, 59 * time.Minute, time.Hour} {
+		now = now.Add(elapsed)
+		if _, err := c.cachedBreaches(context.Background()); err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected the cache to expire after an hour, got %d requests", requests)
+	}
+}
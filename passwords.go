@@ -0,0 +1,299 @@
+package haveibeenpwned
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+//PasswordsAPI URL of the Pwned Passwords API
+const PasswordsAPI = "https://api.pwnedpasswords.com/"
+
+//ErrHashedPassword Returned without calling the API when the plaintext given to PwnedPassword looks like a SHA-1 or NTLM hash. Hashing it again would silently search for the wrong value; pass SHA-1 hashes to PwnedPasswordHash and NTLM hashes to PasswordPwnedNTLM instead.
+var ErrHashedPassword = errors.New("the password looks like a SHA-1 or NTLM hash, use PwnedPasswordHash for hashes")
+
+//PasswordResult Outcome of a password check. Prefix is the only part of the hash that was sent to the API, which can be logged to show the password never left the process.
+type PasswordResult struct {
+	Count  int
+	Prefix string
+	Found  bool
+	//Response Raw body of the range response the count was read from, kept only when Client.KeepRangeResponses is set, to archive exactly what the API returned.
+	Response string
+}
+
+//PwnedPassword Returns how many times the password appears in the Pwned Passwords corpus. Only the first 5 characters of its SHA-1 hash are sent to the API (k-Anonymity), the remaining of the hash is matched locally against the returned range.
+//A password made of 40 or 32 hexadecimal characters is rejected with ErrHashedPassword, since it is most likely a SHA-1 or NTLM hash passed by mistake.
+func (c *Client) PwnedPassword(password string) (int, error) {
+	return c.PwnedPasswordContext(context.Background(), password)
+}
+
+//PwnedPasswordContext Same as PwnedPassword, the request being bound to ctx.
+func (c *Client) PwnedPasswordContext(ctx context.Context, password string) (int, error) {
+	result, err := c.CheckPasswordContext(ctx, password)
+	return result.Count, err
+}
+
+//CheckPassword Same as PwnedPassword, also returning the hash prefix sent to the API.
+func (c *Client) CheckPassword(password string) (PasswordResult, error) {
+	return c.CheckPasswordContext(context.Background(), password)
+}
+
+//CheckPasswordContext Same as CheckPassword, the request being bound to ctx.
+func (c *Client) CheckPasswordContext(ctx context.Context, password string) (PasswordResult, error) {
+	if looksHashed(password) {
+		return PasswordResult{}, ErrHashedPassword
+	}
+	sum := sha1.Sum([]byte(password))
+	return c.checkHash(ctx, hex.EncodeToString(sum[:]))
+}
+
+//PwnedPasswordHash Returns how many times the password with the given hexadecimal SHA-1 hash appears in the Pwned Passwords corpus, for callers that only have the hash at hand.
+func (c *Client) PwnedPasswordHash(hash string) (int, error) {
+	return c.PwnedPasswordHashContext(context.Background(), hash)
+}
+
+//PwnedPasswordHashContext Same as PwnedPasswordHash, the request being bound to ctx.
+func (c *Client) PwnedPasswordHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := c.checkHash(ctx, hash)
+	return result.Count, err
+}
+
+func (c *Client) checkHash(ctx context.Context, hash string) (PasswordResult, error) {
+	if len(hash) != sha1.Size*2 || !isHex(hash) {
+		return PasswordResult{}, fmt.Errorf("invalid SHA-1 hash %q, expected 40 hexadecimal characters", hash)
+	}
+	hash = strings.ToUpper(hash)
+	result := PasswordResult{Prefix: hash[:5]}
+
+	rr, err := c.rangeSearch(ctx, result.Prefix, false)
+	if err != nil {
+		return result, err
+	}
+	result.Count = rr.suffixes[hash[5:]]
+	result.Response = rr.body
+	result.Found = result.Count > 0
+	return result, nil
+}
+
+//PasswordPwnedNTLM Reports whether the password with the given hexadecimal NTLM hash, like the ones stored by Active Directory, appears at least threshold times in the Pwned Passwords corpus, along with how many times it does. The range is searched in NTLM mode, so the hash is never sent whole.
+func (c *Client) PasswordPwnedNTLM(ntlmHash string, threshold int) (bool, int, error) {
+	return c.PasswordPwnedNTLMContext(context.Background(), ntlmHash, threshold)
+}
+
+//PasswordPwnedNTLMContext Same as PasswordPwnedNTLM, the request being bound to ctx.
+func (c *Client) PasswordPwnedNTLMContext(ctx context.Context, ntlmHash string, threshold int) (bool, int, error) {
+	if len(ntlmHash) != 32 || !isHex(ntlmHash) {
+		return false, 0, fmt.Errorf("invalid NTLM hash %q, expected 32 hexadecimal characters", ntlmHash)
+	}
+	hash := strings.ToUpper(ntlmHash)
+
+	rr, err := c.rangeSearch(ctx, hash[:5], true)
+	if err != nil {
+		return false, 0, err
+	}
+	count := rr.suffixes[hash[5:]]
+	return count > 0 && count >= threshold, count, nil
+}
+
+//PasswordPwnedBoth Returns how many times the password appears in the Pwned Passwords corpus when searched by its SHA-1 hash and by its NTLM hash, to audit it against both kinds of stored hashes. The two range lookups run concurrently. Like PwnedPassword, it rejects passwords that look like hashes with ErrHashedPassword.
+func (c *Client) PasswordPwnedBoth(password string) (sha1Count, ntlmCount int, err error) {
+	return c.PasswordPwnedBothContext(context.Background(), password)
+}
+
+//PasswordPwnedBothContext Same as PasswordPwnedBoth, the requests being bound to ctx.
+func (c *Client) PasswordPwnedBothContext(ctx context.Context, password string) (sha1Count, ntlmCount int, err error) {
+	if looksHashed(password) {
+		return 0, 0, ErrHashedPassword
+	}
+
+	var ntlmErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, ntlmCount, ntlmErr = c.PasswordPwnedNTLMContext(ctx, ntlmHash(password), 0)
+	}()
+
+	result, err := c.CheckPasswordContext(ctx, password)
+	<-done
+
+	if err != nil {
+		return 0, 0, err
+	}
+	if ntlmErr != nil {
+		return 0, 0, ntlmErr
+	}
+	return result.Count, ntlmCount, nil
+}
+
+//ntlmHash Returns the hexadecimal NTLM hash of the password: the MD4 of its UTF-16LE encoding.
+func ntlmHash(password string) string {
+	var encoded []byte
+	for _, u := range utf16.Encode([]rune(password)) {
+		encoded = append(encoded, byte(u), byte(u>>8))
+	}
+	sum := md4Sum(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+//IsPasswordPwned Reports whether the password appears at least once in the Pwned Passwords corpus.
+func (c *Client) IsPasswordPwned(password string) (bool, error) {
+	return c.IsPasswordPwnedContext(context.Background(), password)
+}
+
+//IsPasswordPwnedContext Same as IsPasswordPwned, the request being bound to ctx.
+func (c *Client) IsPasswordPwnedContext(ctx context.Context, password string) (bool, error) {
+	count, err := c.PwnedPasswordContext(ctx, password)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+//PasswordRange Returns the suffixes of all the hashes starting with the 5 hexadecimal characters prefix, along with how many times each was seen, exactly as returned by the range API.
+func (c *Client) PasswordRange(prefix string) (map[string]int, error) {
+	return c.PasswordRangeContext(context.Background(), prefix)
+}
+
+//PasswordRangeContext Same as PasswordRange, the request being bound to ctx.
+func (c *Client) PasswordRangeContext(ctx context.Context, prefix string) (map[string]int, error) {
+	if !validPrefix(prefix) {
+		return nil, fmt.Errorf("invalid hash prefix %q, expected 5 hexadecimal characters", prefix)
+	}
+	rr, err := c.rangeSearch(ctx, strings.ToUpper(prefix), false)
+	if err != nil {
+		return nil, err
+	}
+	suffixes := make(map[string]int, len(rr.suffixes))
+	for suffix, count := range rr.suffixes {
+		suffixes[suffix] = count
+	}
+	return suffixes, nil
+}
+
+func validPrefix(prefix string) bool {
+	return len(prefix) == 5 && isHex(prefix)
+}
+
+//looksHashed Reports whether the password has the shape of a hexadecimal SHA-1 (40 characters) or NTLM (32 characters) hash.
+func looksHashed(password string) bool {
+	return (len(password) == 40 || len(password) == 32) && isHex(password)
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) passwordsURL() string {
+	if c.PasswordsURL != "" {
+		return c.PasswordsURL
+	}
+	return PasswordsAPI
+}
+
+//rangeSearch Returns the hash suffixes and their counts for a 5 characters prefix, of NTLM hashes when ntlm is set and SHA-1 ones otherwise, served from the range cache when RangeCacheSize is set. A range cached without its body is fetched again when KeepRangeResponses asks for it.
+func (c *Client) rangeSearch(ctx context.Context, prefix string, ntlm bool) (rangeResponse, error) {
+	key := prefix
+	if ntlm {
+		key = "ntlm:" + prefix
+	}
+	cache := c.rangeLRU()
+	if cache != nil {
+		if rr, ok := cache.get(key); ok && (!c.KeepRangeResponses || rr.(rangeResponse).body != "") {
+			return rr.(rangeResponse), nil
+		}
+	}
+
+	rr, err := c.fetchRange(ctx, prefix, ntlm)
+	if err != nil {
+		return rr, err
+	}
+	if cache != nil {
+		cache.set(key, rr)
+	}
+	return rr, nil
+}
+
+//rangeResponse Parsed range response. body is only kept when KeepRangeResponses is set.
+type rangeResponse struct {
+	suffixes map[string]int
+	body     string
+}
+
+//rangeLRU Returns the range cache, creating it on first use, or nil when RangeCacheSize is not set.
+func (c *Client) rangeLRU() *lru {
+	if c.RangeCacheSize <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ranges == nil {
+		c.ranges = newLRU(c.RangeCacheSize)
+	}
+	return c.ranges
+}
+
+func (c *Client) fetchRange(ctx context.Context, prefix string, ntlm bool) (rangeResponse, error) {
+	var rr rangeResponse
+
+	u := c.passwordsURL() + "range/" + prefix
+	if ntlm {
+		u += "?mode=ntlm"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return rr, err
+	}
+	req.Header.Set("User-Agent", "Go/1.15")
+
+	ctx, span := c.startSpan(ctx, "range")
+	defer span.End()
+
+	res, err := c.doHTTP(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		return rr, err
+	}
+	span.SetAttribute("http.status_code", res.StatusCode)
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		err := fmt.Errorf("unexpected status from the range API: %s", res.Status)
+		span.RecordError(err)
+		return rr, err
+	}
+
+	body, err := c.readBody(res)
+	if err != nil {
+		return rr, err
+	}
+
+	if c.KeepRangeResponses {
+		rr.body = string(body)
+	}
+	rr.suffixes = make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return rr, fmt.Errorf("malformed range line %q: %v", scanner.Text(), err)
+		}
+		rr.suffixes[strings.ToUpper(parts[0])] = count
+	}
+	return rr, scanner.Err()
+}
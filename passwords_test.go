@@ -0,0 +1,234 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// "password" hashes to 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const passwordRange = "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n011053FD0102E94D6AE2F8B83D76FAF94F6:1\r\n"
+
+func newTestPasswordsClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{PasswordsURL: server.URL + "/"}
+}
+
+func TestIsPasswordPwned(t *testing.T) {
+	var path string
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(passwordRange))
+	})
+
+	pwned, err := c.IsPasswordPwned("password")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if path != "/range/5BAA6" {
+		t.Errorf("expected /range/5BAA6, got %s", path)
+	}
+	if !pwned {
+		t.Error("expected password to be pwned")
+	}
+
+	pwned, err = c.IsPasswordPwned("not in the range")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if pwned {
+		t.Error("expected password not to be pwned")
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(passwordRange))
+	})
+
+	result, err := c.CheckPassword("password")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	expected := PasswordResult{Count: 9545824, Prefix: "5BAA6", Found: true}
+	if result != expected {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	c.KeepRangeResponses = true
+	result, err = c.CheckPassword("password")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if result.Response != passwordRange {
+		t.Errorf("expected the raw range response, got %q", result.Response)
+	}
+}
+
+func TestPwnedPasswordHash(t *testing.T) {
+	var calls int
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(passwordRange))
+	})
+
+	count, err := c.PwnedPasswordHash("5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if count != 9545824 {
+		t.Errorf("expected 9545824, got %d", count)
+	}
+
+	hashes := []string{
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", // SHA-1 of "password"
+		"8846F7EAEE8FB117AD06BDD830B7586C",         // NTLM of "password"
+	}
+	for _, hash := range hashes {
+		if _, err := c.PwnedPassword(hash); !errors.Is(err, ErrHashedPassword) {
+			t.Errorf("%s: expected ErrHashedPassword, got %v", hash, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected hashed passwords to be rejected without calling the API, got %d calls", calls)
+	}
+}
+
+func TestPasswordPwnedNTLM(t *testing.T) {
+	var query string
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Path + "?" + r.URL.RawQuery
+		w.Write([]byte("7EAEE8FB117AD06BDD830B7586C:4\r\n"))
+	})
+
+	pwned, count, err := c.PasswordPwnedNTLM("8846f7eaee8fb117ad06bdd830b7586c", 5)
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if query != "/range/8846F?mode=ntlm" {
+		t.Errorf("expected the range to be searched in NTLM mode, got %s", query)
+	}
+	if pwned || count != 4 {
+		t.Errorf("expected 4 occurrences under the threshold, got %v and %d", pwned, count)
+	}
+
+	if pwned, _, _ := c.PasswordPwnedNTLM("8846F7EAEE8FB117AD06BDD830B7586C", 4); !pwned {
+		t.Error("expected the threshold to be inclusive")
+	}
+}
+
+func TestPasswordPwnedBoth(t *testing.T) {
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mode") == "ntlm" {
+			w.Write([]byte("7EAEE8FB117AD06BDD830B7586C:4\r\n"))
+			return
+		}
+		w.Write([]byte(passwordRange))
+	})
+
+	sha1Count, ntlmCount, err := c.PasswordPwnedBoth("password")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if sha1Count != 9545824 || ntlmCount != 4 {
+		t.Errorf("expected 9545824 and 4, got %d and %d", sha1Count, ntlmCount)
+	}
+}
+
+func TestPasswordsContextVariants(t *testing.T) {
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request with a canceled context, got %s", r.URL.Path)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"PwnedPasswordContext": func() error {
+			_, err := c.PwnedPasswordContext(ctx, "password")
+			return err
+		},
+		"CheckPasswordContext": func() error {
+			_, err := c.CheckPasswordContext(ctx, "password")
+			return err
+		},
+		"PwnedPasswordHashContext": func() error {
+			_, err := c.PwnedPasswordHashContext(ctx, "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8")
+			return err
+		},
+		"PasswordRangeContext": func() error {
+			_, err := c.PasswordRangeContext(ctx, "5BAA6")
+			return err
+		},
+		"IsPasswordPwnedContext": func() error {
+			_, err := c.IsPasswordPwnedContext(ctx, "password")
+			return err
+		},
+		"PasswordPwnedBothContext": func() error {
+			_, _, err := c.PasswordPwnedBothContext(ctx, "password")
+			return err
+		},
+		"PasswordPwnedNTLMContext": func() error {
+			_, _, err := c.PasswordPwnedNTLMContext(ctx, "8846F7EAEE8FB117AD06BDD830B7586C", 1)
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
+
+func TestPasswordPwnedBothRejectsHashes(t *testing.T) {
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request for a hashed password, got %s", r.URL)
+	})
+	if _, _, err := c.PasswordPwnedBoth("8846F7EAEE8FB117AD06BDD830B7586C"); !errors.Is(err, ErrHashedPassword) {
+		t.Errorf("expected ErrHashedPassword, got %v", err)
+	}
+}
+
+func TestRangeCache(t *testing.T) {
+	requests := 0
+	c := newTestPasswordsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(passwordRange))
+	})
+	c.RangeCacheSize = 10
+
+	for i := 0; i < 3; i++ {
+		count, err := c.PwnedPassword("password")
+		if err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+		if count != 9545824 {
+			t.Errorf("expected 9545824, got %d", count)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	suffixes, err := c.PasswordRange("5BAA6")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	suffixes["1E4C9B93F3F0682250B6CF8331B7EE68FD8"] = 0
+	if count, _ := c.PwnedPassword("password"); count != 9545824 {
+		t.Errorf("expected the cached range to be unchanged by the caller, got %d", count)
+	}
+
+	c.KeepRangeResponses = true
+	result, err := c.CheckPassword("password")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if result.Response != passwordRange || requests != 2 {
+		t.Errorf("expected the range to be fetched again with its body, got %q after %d requests", result.Response, requests)
+	}
+	if result, _ := c.CheckPassword("password"); result.Response != passwordRange || requests != 2 {
+		t.Errorf("expected the range and its body to be cached, got %q after %d requests", result.Response, requests)
+	}
+}
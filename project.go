@@ -0,0 +1,12 @@
+//go:build go1.18
+
+package haveibeenpwned
+
+//ProjectBreaches Maps each breach to the value returned by fn, like a small view of the fields a response needs, keeping their order.
+func ProjectBreaches[T any](breaches []BreachModel, fn func(BreachModel) T) []T {
+	projected := make([]T, 0, len(breaches))
+	for _, b := range breaches {
+		projected = append(projected, fn(b))
+	}
+	return projected
+}
@@ -0,0 +1,20 @@
+//go:build go1.18
+
+package haveibeenpwned
+
+import "testing"
+
+func TestProjectBreaches(t *testing.T) {
+	type view struct {
+		Name  string
+		Count int64
+	}
+	breaches := []BreachModel{{Name: "Adobe", PwnCount: 152445165, Domain: "adobe.com"}, {Name: "Forum"}}
+
+	views := ProjectBreaches(breaches, func(b BreachModel) view {
+		return view{Name: b.Name, Count: b.PwnCount}
+	})
+	if len(views) != 2 || views[0] != (view{"Adobe", 152445165}) || views[1] != (view{Name: "Forum"}) {
+		t.Errorf("unexpected projection: %v", views)
+	}
+}
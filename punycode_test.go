@@ -0,0 +1,22 @@
+package haveibeenpwned
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	cases := map[string]string{
+		"example.com":    "example.com",
+		"Müller.de":      "xn--mller-kva.de",
+		"bücher.example": "xn--bcher-kva.example",
+		"例え.jp":          "xn--r8jz45g.jp",
+		"mañana.com":     "xn--maana-pta.com",
+		"MÜLLER.De":      "xn--mller-kva.de",
+		"例え。jp":          "xn--r8jz45g.jp",
+		"bücher．example": "xn--bcher-kva.example",
+		"mañana｡COM":     "xn--maana-pta.com",
+	}
+	for in, want := range cases {
+		if got := toASCII(in); got != want {
+			t.Errorf("toASCII(%q): expected %s, got %s", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package haveibeenpwned
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//RateLimit Rate limit values reported by the headers of the latest response that carried any of them. Values missing from that response are left at -1.
+type RateLimit struct {
+	//Service Service of the request that returned the headers.
+	Service string
+	//Limit Requests allowed in the current window.
+	Limit int
+	//Remaining Requests left in the current window.
+	Remaining int
+	//Reset Value of the reset header, in seconds.
+	Reset int
+	//RetryAfter Time to wait before retrying, sent along with 429 responses.
+	RetryAfter time.Duration
+	//Updated When the values were read.
+	Updated time.Time
+}
+
+//LastRateLimit Returns the latest rate limit values seen on a response, and false if no response carried any rate limit header yet.
+func (c *Client) LastRateLimit() (RateLimit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit, !c.rateLimit.Updated.IsZero()
+}
+
+func (c *Client) trackRateLimit(service string, header http.Header) {
+	rl := RateLimit{
+		Service:   service,
+		Limit:     headerInt(header, "X-RateLimit-Limit", "X-Rate-Limit-Limit"),
+		Remaining: headerInt(header, "X-RateLimit-Remaining", "X-Rate-Limit-Remaining"),
+		Reset:     headerInt(header, "X-RateLimit-Reset", "X-Rate-Limit-Reset"),
+	}
+	if seconds := headerInt(header, "Retry-After"); seconds >= 0 {
+		rl.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	if rl.Limit < 0 && rl.Remaining < 0 && rl.Reset < 0 && rl.RetryAfter == 0 {
+		return
+	}
+	rl.Updated = c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rateLimit = rl
+}
+
+//headerInt Returns the value of the first of the keys present in header, or -1 if none is present or valid.
+func headerInt(header http.Header, keys ...string) int {
+	for _, key := range keys {
+		if v := header.Get(key); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				return n
+			}
+		}
+	}
+	return -1
+}
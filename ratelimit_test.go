@@ -0,0 +1,41 @@
+package haveibeenpwned
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLastRateLimit(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/breaches/" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "3")
+		w.Header().Set("Retry-After", "2")
+		w.Write([]byte(`{"Name":"Adobe"}`))
+	})
+
+	if _, ok := c.LastRateLimit(); ok {
+		t.Fatal("expected no rate limit before any request")
+	}
+	if _, err := c.Breach("Adobe"); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if _, err := c.Breaches(""); err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+
+	rl, ok := c.LastRateLimit()
+	if !ok {
+		t.Fatal("expected rate limit values")
+	}
+	if rl.Service != "breach" || rl.Limit != 10 || rl.Remaining != 3 || rl.Reset != -1 {
+		t.Errorf("unexpected rate limit values: %+v", rl)
+	}
+	if rl.RetryAfter != 2*time.Second {
+		t.Errorf("expected a retry after of 2s, got %s", rl.RetryAfter)
+	}
+}
@@ -0,0 +1,39 @@
+package haveibeenpwned
+
+import "sort"
+
+//DataClassRemediation Action item advised when a data class is exposed. Data classes missing from the map need no action. It can be changed to tune RemediationSteps.
+var DataClassRemediation = map[string]string{
+	DataClassPasswords:             "Change your password on the breached site and anywhere you reused it",
+	DataClassHistoricalPasswords:   "Change your password on the breached site and anywhere you reused it",
+	DataClassPasswordHints:         "Change your password on the breached site and anywhere you reused it",
+	DataClassAuthTokens:            "Sign out of all sessions and revoke any API tokens on the breached site",
+	DataClassCreditCards:           "Contact your bank to cancel the card and review recent charges",
+	DataClassPartialCreditCardData: "Review your card statements for unknown charges",
+	DataClassBankAccountNumbers:    "Contact your bank and watch your account for unknown transfers",
+	DataClassSocialSecurityNumbers: "Freeze your credit and watch for identity theft",
+	DataClassGovernmentIssuedIDs:   "Report the exposure to the issuing authority and watch for identity theft",
+	DataClassSecurityQuestions:     "Change your security questions and answers",
+	DataClassEmailAddresses:        "Be wary of phishing emails referring to the breach",
+	DataClassPhoneNumbers:          "Be wary of scam calls and text messages",
+	DataClassPhysicalAddresses:     "Be wary of scam letters and deliveries",
+}
+
+//RemediationSteps Returns the action items advised by DataClassRemediation for the data classes exposed by the breaches, without duplicates, the most severe exposures first.
+func RemediationSteps(breaches []BreachModel) []string {
+	classes := uniqueDataClasses(breaches)
+	sort.SliceStable(classes, func(i, j int) bool {
+		return DataClassSeverity[classes[i]] > DataClassSeverity[classes[j]]
+	})
+
+	seen := make(map[string]bool)
+	steps := make([]string, 0)
+	for _, dc := range classes {
+		step, ok := DataClassRemediation[dc]
+		if ok && !seen[step] {
+			seen[step] = true
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
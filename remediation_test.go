@@ -0,0 +1,22 @@
+package haveibeenpwned
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemediationSteps(t *testing.T) {
+	breaches := []BreachModel{
+		{DataClasses: []string{DataClassEmailAddresses, DataClassPasswords, DataClassUsernames}},
+		{DataClasses: []string{DataClassHistoricalPasswords, DataClassCreditCards}},
+	}
+
+	expected := []string{
+		DataClassRemediation[DataClassPasswords],
+		DataClassRemediation[DataClassCreditCards],
+		DataClassRemediation[DataClassEmailAddresses],
+	}
+	if steps := RemediationSteps(breaches); !reflect.DeepEqual(steps, expected) {
+		t.Errorf("expected %q, got %q", expected, steps)
+	}
+}
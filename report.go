@@ -0,0 +1,278 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+//BreachReport A breach along with values computed from it, ready to be rendered.
+type BreachReport struct {
+	BreachModel
+	//Age Time elapsed since the BreachDate, zero when it cannot be parsed.
+	Age time.Duration
+	//Severity Severity of the breach, see SeverityOf.
+	Severity Severity
+	//HasPasswords Whether the breach exposed passwords.
+	HasPasswords bool
+}
+
+//NewBreachReport Computes the report of a single breach.
+func NewBreachReport(b BreachModel) BreachReport {
+	return newBreachReport(b, time.Now())
+}
+
+//newBreachReport Computes the report of a single breach, its Age being measured at now.
+func newBreachReport(b BreachModel, now time.Time) BreachReport {
+	r := BreachReport{
+		BreachModel:  b,
+		Severity:     SeverityOf(b),
+		HasPasswords: b.HasPasswords(),
+	}
+	if date, err := time.Parse(BreachDateLayout, b.BreachDate); err == nil {
+		r.Age = now.Sub(date)
+	}
+	return r
+}
+
+//AccountBreachReport Fetches the breaches of the account, including unverified ones, and computes the report of each of them.
+func (c *Client) AccountBreachReport(account string) ([]BreachReport, error) {
+	return c.AccountBreachReportContext(context.Background(), account)
+}
+
+//AccountBreachReportContext Same as AccountBreachReport, the request being bound to ctx.
+func (c *Client) AccountBreachReportContext(ctx context.Context, account string) ([]BreachReport, error) {
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	reports := make([]BreachReport, 0, len(breaches))
+	for _, b := range breaches {
+		reports = append(reports, newBreachReport(b, now))
+	}
+	return reports, nil
+}
+
+//RelevantBreaches Fetches the breaches of the account exposing any of the given data classes, and returns their reports sorted by Severity, the most severe first.
+func (c *Client) RelevantBreaches(account string, classes []string) ([]BreachReport, error) {
+	return c.RelevantBreachesContext(context.Background(), account, classes)
+}
+
+//RelevantBreachesContext Same as RelevantBreaches, the request being bound to ctx.
+func (c *Client) RelevantBreachesContext(ctx context.Context, account string, classes []string) ([]BreachReport, error) {
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	reports := make([]BreachReport, 0)
+	for _, b := range breaches {
+		for _, class := range classes {
+			if b.Exposed(class) {
+				reports = append(reports, newBreachReport(b, now))
+				break
+			}
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Severity > reports[j].Severity
+	})
+	return reports, nil
+}
+
+//BreachedAccountBetween Fetches the breaches of the account, including unverified ones, and returns the ones whose BreachDate falls between from and to, both included.
+func (c *Client) BreachedAccountBetween(account string, from, to time.Time) ([]BreachModel, error) {
+	return c.BreachedAccountBetweenContext(context.Background(), account, from, to)
+}
+
+//BreachedAccountBetweenContext Same as BreachedAccountBetween, the request being bound to ctx.
+func (c *Client) BreachedAccountBetweenContext(ctx context.Context, account string, from, to time.Time) ([]BreachModel, error) {
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, true)
+	if err != nil {
+		return nil, err
+	}
+	return BreachedBetween(breaches, from, to), nil
+}
+
+//NewBreachesSince Fetches the breaches of the account, including unverified ones, and returns the ones whose Name is not in known, like the breaches already notified about. Names are compared case insensitively.
+func (c *Client) NewBreachesSince(account string, known []string) ([]BreachModel, error) {
+	return c.NewBreachesSinceContext(context.Background(), account, known)
+}
+
+//NewBreachesSinceContext Same as NewBreachesSince, the request being bound to ctx.
+func (c *Client) NewBreachesSinceContext(ctx context.Context, account string, known []string) ([]BreachModel, error) {
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(known))
+	for _, name := range known {
+		seen[strings.ToLower(name)] = true
+	}
+	fresh := make([]BreachModel, 0)
+	for _, b := range breaches {
+		if !seen[strings.ToLower(b.Name)] {
+			fresh = append(fresh, b)
+		}
+	}
+	return fresh, nil
+}
+
+//InSensitiveBreach Reports whether the account, including its unverified breaches, appears in any sensitive breach. Sensitive breaches are only returned to authenticated searches, which all account searches are.
+func (c *Client) InSensitiveBreach(account string) (bool, error) {
+	return c.InSensitiveBreachContext(context.Background(), account)
+}
+
+//InSensitiveBreachContext Same as InSensitiveBreach, the request being bound to ctx.
+func (c *Client) InSensitiveBreachContext(ctx context.Context, account string) (bool, error) {
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, true)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, b := range breaches {
+		if b.IsSensitive {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+//AccountReport Everything known about an account: its breaches and pastes. PastesSkipped is set when the account is not an email address, since only those can be searched for pastes.
+type AccountReport struct {
+	Account       string
+	Breaches      []BreachModel
+	Pastes        []PasteModel
+	PastesSkipped bool
+}
+
+//Account Fetches the breaches, including unverified ones, and the pastes of the account. Pastes are not looked up for usernames and other accounts that are not email addresses, which the API would reject. A section that is not found is left empty, even when NotFoundIsError is set.
+func (c *Client) Account(account string) (AccountReport, error) {
+	return c.AccountContext(context.Background(), account)
+}
+
+//AccountContext Same as Account, the requests being bound to ctx.
+func (c *Client) AccountContext(ctx context.Context, account string) (AccountReport, error) {
+	report := AccountReport{Account: account}
+
+	breaches, err := c.BreachedAccountContext(ctx, account, "", false, true)
+	if err != nil && err != ErrNotFound {
+		return report, err
+	}
+	report.Breaches = breaches
+
+	if !validEmail(account) {
+		report.Pastes = make([]PasteModel, 0)
+		report.PastesSkipped = true
+		return report, nil
+	}
+
+	pastes, err := c.PasteAccountContext(ctx, account)
+	if err != nil && err != ErrNotFound {
+		return report, err
+	}
+	report.Pastes = pastes
+
+	return report, nil
+}
+
+//PublicBreaches Returns the breaches of the report that are not sensitive.
+func (report AccountReport) PublicBreaches() []BreachModel {
+	public := make([]BreachModel, 0, len(report.Breaches))
+	for _, b := range report.Breaches {
+		if !b.IsSensitive {
+			public = append(public, b)
+		}
+	}
+	return public
+}
+
+//SensitiveBreaches Returns the sensitive breaches of the report, which HIBP hides from public searches, so they can be rendered behind a warning or an opt-in.
+func (report AccountReport) SensitiveBreaches() []BreachModel {
+	sensitive := make([]BreachModel, 0)
+	for _, b := range report.Breaches {
+		if b.IsSensitive {
+			sensitive = append(sensitive, b)
+		}
+	}
+	return sensitive
+}
+
+//AccountCounts Returns only the number of breaches, including unverified ones, and of pastes of the account. Both lookups run concurrently and the breaches are fetched truncated, to transfer and parse as little as possible. An account that is not found counts 0 even when NotFoundIsError is set. Like Account, pastes are not looked up for accounts that are not email addresses.
+func (c *Client) AccountCounts(ctx context.Context, email string) (breaches int, pastes int, err error) {
+	var pastesErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if !validEmail(email) {
+			return
+		}
+		var p []PasteModel
+		p, pastesErr = c.PasteAccountContext(ctx, email)
+		if pastesErr == ErrNotFound {
+			pastesErr = nil
+		}
+		pastes = len(p)
+	}()
+
+	b, err := c.breachedAccount(ctx, email, "", true, true)
+	if err == ErrNotFound {
+		err = nil
+	}
+	breaches = len(b)
+	<-done
+
+	if err != nil {
+		return 0, 0, err
+	}
+	if pastesErr != nil {
+		return 0, 0, pastesErr
+	}
+	return breaches, pastes, nil
+}
+
+//Summary Digests the report in a single line, like "3 breaches, 1 paste, email addresses+passwords exposed, latest 2023-05".
+func (report AccountReport) Summary() string {
+	parts := []string{plural(len(report.Breaches), "breach", "breaches")}
+	if len(report.Pastes) > 0 {
+		parts = append(parts, plural(len(report.Pastes), "paste", "pastes"))
+	}
+	if classes := uniqueDataClasses(report.Breaches); len(classes) > 0 {
+		parts = append(parts, strings.ToLower(strings.Join(classes, "+"))+" exposed")
+	}
+	if _, latest, err := DateRange(report.Breaches); err == nil {
+		parts = append(parts, "latest "+latest.Format("2006-01"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+//uniqueDataClasses Returns the data classes exposed by the breaches, in order of first appearance. Data classes differing only by case are listed once, under the first spelling met.
+func uniqueDataClasses(breaches []BreachModel) []string {
+	seen := make(map[string]bool)
+	classes := make([]string, 0)
+	for _, b := range breaches {
+		for _, dc := range b.DataClasses {
+			if key := strings.ToLower(dc); !seen[key] {
+				seen[key] = true
+				classes = append(classes, dc)
+			}
+		}
+	}
+	return classes
+}
@@ -0,0 +1,217 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAccountBreachReport(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe","BreachDate":"2013-10-04","DataClasses":["Email addresses","Passwords"]},{"Name":"Unknown"}]`))
+	})
+
+	reports, err := c.AccountBreachReport("test@example.com")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(reports))
+	}
+	if !reports[0].HasPasswords || reports[0].Severity != SeverityCritical || reports[0].Age <= 0 {
+		t.Errorf("unexpected report for Adobe: %+v", reports[0])
+	}
+	if reports[1].HasPasswords || reports[1].Severity != SeverityLow || reports[1].Age != 0 {
+		t.Errorf("unexpected report for Unknown: %+v", reports[1])
+	}
+
+	WithClock(func() time.Time { return time.Date(2013, 10, 5, 0, 0, 0, 0, time.UTC) })(c)
+	reports, err = c.AccountBreachReport("test@example.com")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if reports[0].Age != 24*time.Hour {
+		t.Errorf("expected Adobe to be a day old by the client clock, got %s", reports[0].Age)
+	}
+}
+
+func TestAccountSkipsPastesForUsernames(t *testing.T) {
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`[{"Name":"Forum"}]`))
+	})
+
+	report, err := c.Account("johndoe")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if !report.PastesSkipped || len(report.Pastes) != 0 {
+		t.Errorf("expected pastes to be skipped, got %+v", report)
+	}
+	if len(report.Breaches) != 1 {
+		t.Errorf("expected 1 breach, got %d", len(report.Breaches))
+	}
+	if len(paths) != 1 || paths[0] != "/breachedaccount/johndoe" {
+		t.Errorf("expected only the breaches to be requested, got %v", paths)
+	}
+}
+
+func TestAccountNotFoundIsError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/breachedaccount/test@example.com" {
+			w.Write([]byte(`[{"Name":"Adobe"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	c.NotFoundIsError = true
+
+	report, err := c.Account("test@example.com")
+	if err != nil {
+		t.Fatalf("expected no error for an account without pastes, got %v", err)
+	}
+	if len(report.Breaches) != 1 || len(report.Pastes) != 0 {
+		t.Errorf("expected 1 breach and no paste, got %+v", report)
+	}
+
+	report, err = c.Account("clean@example.com")
+	if err != nil {
+		t.Fatalf("expected no error for a clean account, got %v", err)
+	}
+	if len(report.Breaches) != 0 || len(report.Pastes) != 0 {
+		t.Errorf("expected an empty report, got %+v", report)
+	}
+}
+
+func TestNewBreachesSince(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Forum"},{"Name":"Shop"}]`))
+	})
+
+	fresh, err := c.NewBreachesSince("test@example.com", []string{"adobe", "Shop"})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(fresh) != 1 || fresh[0].Name != "Forum" {
+		t.Errorf("expected Forum only, got %v", fresh)
+	}
+}
+
+func TestSensitiveBreaches(t *testing.T) {
+	report := AccountReport{Breaches: []BreachModel{
+		{Name: "Adobe"},
+		{Name: "Ashley", IsSensitive: true},
+		{Name: "Forum"},
+	}}
+
+	if public := report.PublicBreaches(); len(public) != 2 || public[1].Name != "Forum" {
+		t.Errorf("expected Adobe and Forum, got %v", public)
+	}
+	if sensitive := report.SensitiveBreaches(); len(sensitive) != 1 || sensitive[0].Name != "Ashley" {
+		t.Errorf("expected Ashley, got %v", sensitive)
+	}
+}
+
+func TestAccountCounts(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/breachedaccount/test@example.com":
+			if r.URL.RawQuery != "includeUnverified=true" {
+				t.Errorf("expected a truncated lookup, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Forum"},{"Name":"Shop"}]`))
+		case "/pasteaccount/test@example.com":
+			w.Write([]byte(`[{"Source":"Pastebin"},{"Source":"Pastie"}]`))
+		}
+	})
+
+	breaches, pastes, err := c.AccountCounts(context.Background(), "test@example.com")
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if breaches != 3 || pastes != 2 {
+		t.Errorf("expected 3 breaches and 2 pastes, got %d and %d", breaches, pastes)
+	}
+}
+
+func TestAccountCountsNotFoundIsError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	c.NotFoundIsError = true
+
+	breaches, pastes, err := c.AccountCounts(context.Background(), "clean@example.com")
+	if err != nil || breaches != 0 || pastes != 0 {
+		t.Errorf("expected (0, 0, nil) for a clean account, got (%d, %d, %v)", breaches, pastes, err)
+	}
+}
+
+func TestInSensitiveBreach(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/breachedaccount/sensitive@example.com":
+			w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Ashley","IsSensitive":true}]`))
+		case "/breachedaccount/public@example.com":
+			w.Write([]byte(`[{"Name":"Adobe"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	c.NotFoundIsError = true
+
+	for account, expected := range map[string]bool{"sensitive@example.com": true, "public@example.com": false, "clean@example.com": false} {
+		sensitive, err := c.InSensitiveBreach(account)
+		if err != nil {
+			t.Fatalf("%s: response error: %v", account, err)
+		}
+		if sensitive != expected {
+			t.Errorf("%s: expected %v, got %v", account, expected, sensitive)
+		}
+	}
+}
+
+func TestAccountReportSummary(t *testing.T) {
+	report := AccountReport{
+		Breaches: []BreachModel{
+			{Name: "Adobe", BreachDate: "2013-10-04", DataClasses: []string{"Email addresses", "Passwords"}},
+			{Name: "Canva", BreachDate: "2019-05-24", DataClasses: []string{"Email Addresses", "Names"}},
+		},
+		Pastes: []PasteModel{{ID: "abc"}},
+	}
+	expected := "2 breaches, 1 paste, email addresses+passwords+names exposed, latest 2019-05"
+	if s := report.Summary(); s != expected {
+		t.Errorf("expected %q, got %q", expected, s)
+	}
+
+	if s := (AccountReport{}).Summary(); s != "0 breaches" {
+		t.Errorf("expected %q, got %q", "0 breaches", s)
+	}
+
+	single := AccountReport{Breaches: []BreachModel{{Name: "Adobe"}}, Pastes: []PasteModel{{ID: "abc"}, {ID: "def"}}}
+	if s := single.Summary(); s != "1 breach, 2 pastes" {
+		t.Errorf("expected %q, got %q", "1 breach, 2 pastes", s)
+	}
+}
+
+func TestRelevantBreaches(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"Name":"Forum","DataClasses":["Email addresses","Usernames"]},
+			{"Name":"Shop","DataClasses":["Email addresses","Phone numbers"]},
+			{"Name":"Adobe","DataClasses":["Email addresses","Passwords"]}
+		]`))
+	})
+
+	reports, err := c.RelevantBreaches("test@example.com", []string{DataClassPasswords, DataClassPhoneNumbers})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(reports))
+	}
+	if reports[0].Name != "Adobe" || reports[1].Name != "Shop" {
+		t.Errorf("expected [Adobe Shop], got [%s %s]", reports[0].Name, reports[1].Name)
+	}
+}
@@ -0,0 +1,61 @@
+package haveibeenpwned
+
+import "time"
+
+//RiskWeights Points given by RiskWeights.Score to the exposures of an account.
+type RiskWeights struct {
+	//Severity Points given to a breach, by its SeverityOf.
+	Severity map[Severity]int
+	//UnverifiedPercent Share of its points kept by a breach that is not verified.
+	UnverifiedPercent int
+	//Recent Breaches dated within that duration are recent.
+	Recent time.Duration
+	//RecentBonus Points added to a recent breach. Spam lists never get it, being marketing data rather than a compromise.
+	RecentBonus int
+	//Paste Points given to each paste.
+	Paste int
+}
+
+//DefaultRiskWeights Weights used by RiskScore. It can be changed to tune the score.
+var DefaultRiskWeights = RiskWeights{
+	Severity: map[Severity]int{
+		SeverityLow:      5,
+		SeverityMedium:   10,
+		SeverityHigh:     20,
+		SeverityCritical: 30,
+	},
+	UnverifiedPercent: 50,
+	Recent:            2 * 365 * 24 * time.Hour,
+	RecentBonus:       10,
+	Paste:             5,
+}
+
+//RiskScore Returns the privacy risk of an account from 0 to 100, computed from its breaches and pastes with DefaultRiskWeights.
+func RiskScore(breaches []BreachModel, pastes []PasteModel) int {
+	return DefaultRiskWeights.Score(breaches, pastes)
+}
+
+//Score Returns the privacy risk of an account from 0 to 100: the sum of the points given to its breaches and pastes, capped at 100.
+func (w RiskWeights) Score(breaches []BreachModel, pastes []PasteModel) int {
+	return w.ScoreAt(breaches, pastes, time.Now())
+}
+
+//ScoreAt Same as Score, deciding which breaches are recent as of now rather than the current time.
+func (w RiskWeights) ScoreAt(breaches []BreachModel, pastes []PasteModel, now time.Time) int {
+	score := len(pastes) * w.Paste
+	for _, b := range breaches {
+		points := w.Severity[SeverityOf(b)]
+		if date, err := time.Parse(BreachDateLayout, b.BreachDate); err == nil && now.Sub(date) < w.Recent && !b.IsSpam() {
+			points += w.RecentBonus
+		}
+		if !b.IsVerified {
+			points = points * w.UnverifiedPercent / 100
+		}
+		score += points
+	}
+
+	if score > 100 {
+		return 100
+	}
+	return score
+}
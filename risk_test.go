@@ -0,0 +1,38 @@
+package haveibeenpwned
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRiskScore(t *testing.T) {
+	recent := time.Now().AddDate(0, -1, 0).Format(BreachDateLayout)
+	tests := []struct {
+		name     string
+		breaches []BreachModel
+		pastes   []PasteModel
+		score    int
+	}{
+		{"nothing", nil, nil, 0},
+		{"old verified", []BreachModel{{IsVerified: true, BreachDate: "2013-10-04", DataClasses: []string{DataClassPasswords}}}, nil, 30},
+		{"recent verified", []BreachModel{{IsVerified: true, BreachDate: recent, DataClasses: []string{DataClassPasswords}}}, nil, 40},
+		{"recent spam list", []BreachModel{{IsVerified: true, IsSpamList: true, BreachDate: recent}}, nil, 5},
+		{"unverified", []BreachModel{{BreachDate: "2013-10-04", DataClasses: []string{DataClassPhoneNumbers}}}, []PasteModel{{}, {}}, 15},
+		{"capped", []BreachModel{
+			{IsVerified: true, DataClasses: []string{DataClassPasswords}},
+			{IsVerified: true, DataClasses: []string{DataClassCreditCards}},
+			{IsVerified: true, DataClasses: []string{DataClassAuthTokens}},
+			{IsVerified: true, DataClasses: []string{DataClassBankAccountNumbers}},
+		}, nil, 100},
+	}
+	for _, tt := range tests {
+		if score := RiskScore(tt.breaches, tt.pastes); score != tt.score {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.score, score)
+		}
+	}
+
+	old := []BreachModel{{IsVerified: true, BreachDate: "2013-10-04", DataClasses: []string{DataClassPasswords}}}
+	if score := DefaultRiskWeights.ScoreAt(old, nil, time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)); score != 40 {
+		t.Errorf("expected the breach to be recent in 2014, got %d", score)
+	}
+}
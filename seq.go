@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package haveibeenpwned
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+//errStopped Stops streamBreaches when the loop ranging over BreachesSeq breaks.
+var errStopped = errors.New("iteration stopped")
+
+//BreachesSeq Iterates over all breaches in the system while the response is decoded, one breach at a time. An error ends the iteration, yielded along with a zero BreachModel. Breaking out of the loop closes the response.
+func (c *Client) BreachesSeq(ctx context.Context) iter.Seq2[BreachModel, error] {
+	return func(yield func(BreachModel, error) bool) {
+		err := c.streamBreaches(ctx, func(b BreachModel) error {
+			if !yield(b, nil) {
+				return errStopped
+			}
+			return nil
+		})
+		if err != nil && err != errStopped {
+			yield(BreachModel{}, err)
+		}
+	}
+}
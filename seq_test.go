@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package haveibeenpwned
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestBreachesSeq(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Forum"},{"Name":"Shop"}]`))
+	})
+
+	var names []string
+	for b, err := range c.BreachesSeq(context.Background()) {
+		if err != nil {
+			t.Fatalf("response error: %v", err)
+		}
+		names = append(names, b.Name)
+		if b.Name == "Forum" {
+			break
+		}
+	}
+	if len(names) != 2 || names[1] != "Forum" {
+		t.Errorf("expected to stop at Forum, got %v", names)
+	}
+}
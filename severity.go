@@ -0,0 +1,106 @@
+package haveibeenpwned
+
+import "strings"
+
+//Severity How harmful the exposure of a breach is.
+type Severity int
+
+//Severities, from the least to the most harmful.
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+//DataClassSeverity Severity of the exposure of each data class. Data classes missing from the map are SeverityLow. It can be changed to tune SeverityOf.
+var DataClassSeverity = map[string]Severity{
+	DataClassPasswords:             SeverityCritical,
+	DataClassCreditCards:           SeverityCritical,
+	DataClassBankAccountNumbers:    SeverityCritical,
+	DataClassSocialSecurityNumbers: SeverityCritical,
+	DataClassGovernmentIssuedIDs:   SeverityCritical,
+	DataClassAuthTokens:            SeverityCritical,
+	DataClassHistoricalPasswords:   SeverityHigh,
+	DataClassPartialCreditCardData: SeverityHigh,
+	DataClassSecurityQuestions:     SeverityHigh,
+	DataClassPasswordHints:         SeverityHigh,
+	DataClassHealthInsuranceInfo:   SeverityHigh,
+	DataClassSexualOrientations:    SeverityHigh,
+	DataClassPrivateMessages:       SeverityHigh,
+	DataClassPhoneNumbers:          SeverityMedium,
+	DataClassPhysicalAddresses:     SeverityMedium,
+	DataClassDatesOfBirth:          SeverityMedium,
+	DataClassIPAddresses:           SeverityMedium,
+	DataClassNames:                 SeverityMedium,
+	DataClassGeographicLocations:   SeverityMedium,
+}
+
+//SeverityOf Returns the severity of the most harmful data class exposed by the breach, according to DataClassSeverity. Spam lists are not a compromise of the account and are always SeverityLow.
+func SeverityOf(b BreachModel) Severity {
+	severity := SeverityLow
+	if b.IsSpam() {
+		return severity
+	}
+	for _, dc := range b.DataClasses {
+		if s := DataClassSeverity[dc]; s > severity {
+			severity = s
+		}
+	}
+	return severity
+}
+
+//DataClassRanking Data classes from the most to the least harmful to have exposed, for WorstDataClass. Data classes missing from it rank below all the listed ones. It can be changed to tune the ranking.
+var DataClassRanking = []string{
+	DataClassPasswords,
+	DataClassAuthTokens,
+	DataClassCreditCards,
+	DataClassBankAccountNumbers,
+	DataClassSocialSecurityNumbers,
+	DataClassGovernmentIssuedIDs,
+	DataClassHistoricalPasswords,
+	DataClassPartialCreditCardData,
+	DataClassSecurityQuestions,
+	DataClassPasswordHints,
+	DataClassHealthInsuranceInfo,
+	DataClassSexualOrientations,
+	DataClassPrivateMessages,
+	DataClassDatesOfBirth,
+	DataClassPhysicalAddresses,
+	DataClassPhoneNumbers,
+	DataClassIPAddresses,
+	DataClassGeographicLocations,
+	DataClassNames,
+	DataClassUsernames,
+	DataClassEmailAddresses,
+}
+
+//WorstDataClass Returns the data class exposed by the breaches that ranks the highest in DataClassRanking. When none of them is ranked the first one exposed is returned, and "" when there is none.
+func WorstDataClass(breaches []BreachModel) string {
+	classes := uniqueDataClasses(breaches)
+	for _, ranked := range DataClassRanking {
+		for _, dc := range classes {
+			if strings.EqualFold(dc, ranked) {
+				return dc
+			}
+		}
+	}
+	if len(classes) > 0 {
+		return classes[0]
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package haveibeenpwned
+
+import "testing"
+
+func TestSeverityOf(t *testing.T) {
+	tests := []struct {
+		classes  []string
+		severity Severity
+	}{
+		{[]string{DataClassEmailAddresses, DataClassUsernames}, SeverityLow},
+		{[]string{DataClassEmailAddresses, DataClassPhoneNumbers}, SeverityMedium},
+		{[]string{DataClassPasswordHints}, SeverityHigh},
+		{[]string{DataClassNames, DataClassPasswords}, SeverityCritical},
+	}
+	spam := BreachModel{IsSpamList: true, DataClasses: []string{DataClassEmailAddresses, DataClassPasswords}}
+	if s := SeverityOf(spam); s != SeverityLow {
+		t.Errorf("spam list: expected %s, got %s", SeverityLow, s)
+	}
+	for _, tt := range tests {
+		if s := SeverityOf(BreachModel{DataClasses: tt.classes}); s != tt.severity {
+			t.Errorf("%v: expected %s, got %s", tt.classes, tt.severity, s)
+		}
+	}
+}
+
+func TestWorstDataClass(t *testing.T) {
+	tests := []struct {
+		breaches []BreachModel
+		worst    string
+	}{
+		{nil, ""},
+		{[]BreachModel{{DataClasses: []string{"Astrological signs", "Spoken languages"}}}, "Astrological signs"},
+		{[]BreachModel{
+			{DataClasses: []string{DataClassEmailAddresses, DataClassNames}},
+			{DataClasses: []string{DataClassCreditCards, DataClassUsernames}},
+		}, DataClassCreditCards},
+	}
+	for _, tt := range tests {
+		if worst := WorstDataClass(tt.breaches); worst != tt.worst {
+			t.Errorf("expected %q, got %q", tt.worst, worst)
+		}
+	}
+}
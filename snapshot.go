@@ -0,0 +1,53 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+//BreachesSnapshot Full list of breaches as persisted on disk by SaveBreaches, along with when it was fetched so its staleness can be judged.
+type BreachesSnapshot struct {
+	Fetched  time.Time     `json:"fetched"`
+	Breaches []BreachModel `json:"breaches"`
+}
+
+//SaveBreaches Fetches all breaches in the system and writes them to path as a JSON snapshot, for later use without network access through LoadBreaches.
+func (c *Client) SaveBreaches(path string) error {
+	return c.SaveBreachesContext(context.Background(), path)
+}
+
+//SaveBreachesContext Same as SaveBreaches, the request being bound to ctx.
+func (c *Client) SaveBreachesContext(ctx context.Context, path string) error {
+	breaches, err := c.BreachesContext(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(BreachesSnapshot{Fetched: c.now().UTC(), Breaches: breaches})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+//LoadBreaches Returns the breaches of the snapshot written to path by SaveBreaches. Use ReadBreachesSnapshot to also know when they were fetched.
+func LoadBreaches(path string) ([]BreachModel, error) {
+	snapshot, err := ReadBreachesSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Breaches, nil
+}
+
+//ReadBreachesSnapshot Returns the snapshot written to path by SaveBreaches.
+func ReadBreachesSnapshot(path string) (BreachesSnapshot, error) {
+	var snapshot BreachesSnapshot
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
@@ -0,0 +1,35 @@
+package haveibeenpwned
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveBreaches(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe","PwnCount":152445165},{"Name":"Forum"}]`))
+	})
+
+	path := filepath.Join(t.TempDir(), "breaches.json")
+	if err := c.SaveBreaches(path); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	snapshot, err := ReadBreachesSnapshot(path)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if time.Since(snapshot.Fetched) > time.Minute {
+		t.Errorf("expected a recent fetch time, got %v", snapshot.Fetched)
+	}
+
+	breaches, err := LoadBreaches(path)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if len(breaches) != 2 || breaches[0].Name != "Adobe" || breaches[0].PwnCount != 152445165 {
+		t.Errorf("unexpected breaches: %v", breaches)
+	}
+}
@@ -0,0 +1,138 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+//BreachedDomainStream Decodes the breached email addresses of a domain one alias at a time, calling fn with the alias and the names of the breaches it appears in. The API returns the whole domain as a single object, so the response is decoded while it is read instead of being buffered, which keeps memory flat for domains with many mailboxes. The domain must be verified on the dashboard of the API key. The first error returned by fn stops the decoding and is returned.
+func (c *Client) BreachedDomainStream(domain string, fn func(alias string, breaches []string) error) error {
+	return c.BreachedDomainStreamContext(context.Background(), domain, fn)
+}
+
+//BreachedDomainStreamContext Same as BreachedDomainStream, the request being bound to ctx.
+func (c *Client) BreachedDomainStreamContext(ctx context.Context, domain string, fn func(alias string, breaches []string) error) error {
+	body, err := c.openStream(ctx, "breacheddomain", domain, "", true, false)
+	if err != nil || body == nil {
+		return err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		alias, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected an alias, got %v", tok)
+		}
+
+		var breaches []string
+		if err := dec.Decode(&breaches); err != nil {
+			return err
+		}
+		if err := fn(alias, breaches); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+//BreachesFilter Fetches all breaches in the system keeping only the ones pred returns true for. The response is decoded one breach at a time, so the whole list is never held in memory.
+func (c *Client) BreachesFilter(ctx context.Context, pred func(BreachModel) bool) ([]BreachModel, error) {
+	breaches := make([]BreachModel, 0)
+	err := c.streamBreaches(ctx, func(b BreachModel) error {
+		if pred(b) {
+			breaches = append(breaches, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return breaches, nil
+}
+
+//BreachesResume Decodes all breaches in the system one at a time like BreachesFilter, calling fn only with the ones after the breach named afterName, so an interrupted job can be restarted from the last breach it processed. An empty afterName starts from the first breach. It fails when no breach is named afterName, since the position to resume from is then unknown. The first error returned by fn stops the decoding and is returned.
+func (c *Client) BreachesResume(ctx context.Context, afterName string, fn func(BreachModel) error) error {
+	resumed := afterName == ""
+	err := c.streamBreaches(ctx, func(b BreachModel) error {
+		if resumed {
+			return fn(b)
+		}
+		resumed = b.Name == afterName
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !resumed {
+		return fmt.Errorf("no breach named %q to resume after", afterName)
+	}
+	return nil
+}
+
+//streamBreaches Decodes all breaches in the system one at a time, calling fn with each of them. The first error returned by fn stops the decoding and is returned.
+func (c *Client) streamBreaches(ctx context.Context, fn func(BreachModel) error) error {
+	body, err := c.openStream(ctx, "breaches", "", "", true, false)
+	if err != nil || body == nil {
+		return err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		var b BreachModel
+		if err := dec.Decode(&b); err != nil {
+			return err
+		}
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+//openStream Calls the service and returns the body of the response so it can be decoded while it is read, or nil when the API answers 404. The response is not cached, and reading past MaxResponseBytes fails.
+func (c *Client) openStream(ctx context.Context, service, account, domainFilter string, truncate, unverified bool) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, service, account, domainFilter, truncate, unverified)
+	if err != nil {
+		return nil, serviceError(service, account, err)
+	}
+
+	res, err := c.do(service, req)
+	if err != nil {
+		return nil, serviceError(service, account, err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil
+	}
+	if err := checkJSON(res); err != nil {
+		res.Body.Close()
+		return nil, serviceError(service, account, err)
+	}
+	return c.limitBody(res.Body), nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+	return nil
+}
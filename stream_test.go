@@ -0,0 +1,103 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBreachedDomainStream(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/breacheddomain/example.com" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"alias1":["Adobe"],"alias2":["Adobe","Gawker","Stratfor"]}`))
+	})
+
+	aliases := make(map[string][]string)
+	err := c.BreachedDomainStream("example.com", func(alias string, breaches []string) error {
+		aliases[alias] = breaches
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(aliases) != 2 {
+		t.Errorf("expected 2 aliases, got %d", len(aliases))
+	}
+	if len(aliases["alias2"]) != 3 {
+		t.Errorf("expected 3 breaches for alias2, got %v", aliases["alias2"])
+	}
+}
+
+func TestBreachesFilter(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"Name":"Adobe","IsVerified":true,"DataClasses":["Email addresses","Passwords"]},
+			{"Name":"Unverified","DataClasses":["Passwords"]},
+			{"Name":"Forum","IsVerified":true,"DataClasses":["Usernames"]}
+		]`))
+	})
+
+	breaches, err := c.BreachesFilter(context.Background(), func(b BreachModel) bool {
+		return b.IsVerified && b.HasPasswords()
+	})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(breaches) != 1 || breaches[0].Name != "Adobe" {
+		t.Errorf("expected Adobe only, got %v", breaches)
+	}
+}
+
+func TestBreachesResume(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Forum"},{"Name":"Shop"}]`))
+	})
+
+	var names []string
+	err := c.BreachesResume(context.Background(), "Adobe", func(b BreachModel) error {
+		names = append(names, b.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("response error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Forum" || names[1] != "Shop" {
+		t.Errorf("expected to resume after Adobe, got %v", names)
+	}
+
+	err = c.BreachesResume(context.Background(), "Gone", func(b BreachModel) error {
+		t.Errorf("unexpected breach %s", b.Name)
+		return nil
+	})
+	if err == nil {
+		t.Error("expected an error for an unknown breach to resume after")
+	}
+}
+
+func TestStreamMaxResponseBytes(t *testing.T) {
+	body := `{"alias1":["Adobe"],"alias2":["Adobe","Gawker","Stratfor"]}`
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/breaches") {
+			w.Write([]byte(`[{"Name":"Adobe"},{"Name":"Gawker"},{"Name":"Stratfor"}]`))
+			return
+		}
+		w.Write([]byte(body))
+	})
+
+	c.MaxResponseBytes = int64(len(body))
+	if err := c.BreachedDomainStream("example.com", func(string, []string) error { return nil }); err != nil {
+		t.Fatalf("expected a body at the limit to be read, got %v", err)
+	}
+
+	c.MaxResponseBytes = 20
+	err := c.BreachedDomainStream("example.com", func(string, []string) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "exceeds the limit of 20 bytes") {
+		t.Errorf("expected the limit to be enforced, got %v", err)
+	}
+	if _, err := c.BreachesFilter(context.Background(), func(BreachModel) bool { return true }); err == nil || !strings.Contains(err.Error(), "exceeds the limit") {
+		t.Errorf("expected the limit to be enforced when streaming breaches, got %v", err)
+	}
+}
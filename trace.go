@@ -0,0 +1,33 @@
+package haveibeenpwned
+
+import "context"
+
+//Tracer Starts a span around each request sent to the API. It mirrors the Start method of an OpenTelemetry trace.Tracer without depending on it, so an adapter of a few lines is enough to plug one in.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+//Span A span started by a Tracer, named "hibp." followed by the service, like "hibp.breachedaccount". The span covers the request up to the headers of the response.
+type Span interface {
+	//SetAttribute Records an attribute, "hibp.service" and "http.status_code".
+	SetAttribute(key string, value interface{})
+	//RecordError Records the error the request failed with.
+	RecordError(err error)
+	//End Ends the span.
+	End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) RecordError(error)                {}
+func (noopSpan) End()                             {}
+
+func (c *Client) startSpan(ctx context.Context, service string) (context.Context, Span) {
+	if c.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := c.Tracer.Start(ctx, "hibp."+service)
+	span.SetAttribute("hibp.service", service)
+	return ctx, span
+}
@@ -0,0 +1,50 @@
+package haveibeenpwned
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: make(map[string]interface{})}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+func TestTracer(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	tracer := &testTracer{}
+	c.Tracer = tracer
+
+	if _, err := c.BreachedAccount("test@example.com", "", false, false); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "hibp.breachedaccount" || !span.ended {
+		t.Errorf("unexpected span: %+v", span)
+	}
+	if span.attrs["http.status_code"] != http.StatusTooManyRequests || span.err == nil {
+		t.Errorf("expected the status and error to be recorded, got %+v", span)
+	}
+}